@@ -1,14 +1,39 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
-	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
+	"github.com/iSkytran/2023adventofcode/utilities/ahocorasick"
 )
 
-var lookupTable = map[string]int{
+// Words that stand for numbers and their values.
+type vocabulary map[string]int
+
+// Digits are always recognized.
+var digits = vocabulary{
+	"0": 0,
+	"1": 1,
+	"2": 2,
+	"3": 3,
+	"4": 4,
+	"5": 5,
+	"6": 6,
+	"7": 7,
+	"8": 8,
+	"9": 9,
+}
+
+var english = vocabulary{
 	"zero":  0,
 	"one":   1,
 	"two":   2,
@@ -19,85 +44,253 @@ var lookupTable = map[string]int{
 	"seven": 7,
 	"eight": 8,
 	"nine":  9,
-	"0":     0,
-	"1":     1,
-	"2":     2,
-	"3":     3,
-	"4":     4,
-	"5":     5,
-	"6":     6,
-	"7":     7,
-	"8":     8,
-	"9":     9,
-}
-
-func reverse(str string) string {
-	slice := []rune(str)
-	reversed := make([]rune, len(slice))
-	for idx, r := range slice {
-		reversed[len(slice)-idx-1] = r
+}
+
+// Registered vocabularies that can be selected by name.
+var vocabularies = map[string]vocabulary{
+	"english": english,
+	"english-extended": english.merge(vocabulary{
+		"ten":       10,
+		"eleven":    11,
+		"twelve":    12,
+		"thirteen":  13,
+		"fourteen":  14,
+		"fifteen":   15,
+		"sixteen":   16,
+		"seventeen": 17,
+		"eighteen":  18,
+		"nineteen":  19,
+		"twenty":    20,
+		"thirty":    30,
+		"forty":     40,
+		"fifty":     50,
+		"sixty":     60,
+		"seventy":   70,
+		"eighty":    80,
+		"ninety":    90,
+	}),
+	"french": {
+		"zéro":   0,
+		"un":     1,
+		"deux":   2,
+		"trois":  3,
+		"quatre": 4,
+		"cinq":   5,
+		"six":    6,
+		"sept":   7,
+		"huit":   8,
+		"neuf":   9,
+	},
+	"german": {
+		"null":   0,
+		"eins":   1,
+		"zwei":   2,
+		"drei":   3,
+		"vier":   4,
+		"fünf":   5,
+		"sechs":  6,
+		"sieben": 7,
+		"acht":   8,
+		"neun":   9,
+	},
+}
+
+func (vocab vocabulary) merge(other vocabulary) vocabulary {
+	merged := make(vocabulary)
+	for word, value := range vocab {
+		merged[word] = value
+	}
+	for word, value := range other {
+		merged[word] = value
 	}
-	return string(reversed)
+	return merged
 }
 
-func part1(path string) {
-	scanner, file := utilities.OpenFile(path)
+func loadVocabulary(nameOrPath string) (vocabulary, error) {
+	if vocab, ok := vocabularies[nameOrPath]; ok {
+		return vocab, nil
+	}
+
+	// Otherwise read "word value" pairs from a file, one per line.
+	file, err := os.Open(nameOrPath)
+	if err != nil {
+		names := make([]string, 0)
+		for name := range vocabularies {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown vocabulary %q, registered vocabularies are %s: %w",
+			nameOrPath, strings.Join(names, ", "), err)
+	}
 	defer file.Close()
+	scanner := bufio.NewScanner(file)
 
-	// Compile regex.
-	regex := regexp.MustCompile("[0-9]")
+	vocab := make(vocabulary)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
 
-	// Iterate per line.
-	sum := 0
-	for scanner.Scan() {
-		value := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s line %d: expected a word and a value", nameOrPath, lineNum)
+		}
+		value, err := strconv.Atoi(fields[1])
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("%s line %d: invalid value %q", nameOrPath, lineNum, fields[1])
+		}
+		vocab[fields[0]] = value
+	}
+	return vocab, nil
+}
 
-		// Grab first number.
-		first := regex.FindString(value)
-		firstInt := lookupTable[first]
+func (vocab vocabulary) matcher() *ahocorasick.Matcher {
+	// Sort so the dictionary is the same every run.
+	dictionary := make([]string, 0)
+	for word := range vocab {
+		dictionary = append(dictionary, word)
+	}
+	slices.Sort(dictionary)
+	return ahocorasick.New(dictionary)
+}
 
-		// Grab last number
-		last := reverse(regex.FindString(reverse(value)))
-		lastInt := lookupTable[last]
+func firstAndLast(matcher *ahocorasick.Matcher, line string) (ahocorasick.Match, ahocorasick.Match, bool) {
+	// Track the earliest and latest starting matches in one forward pass, which
+	// also sees overlaps like "twone". Longer words win ties.
+	var first, last ahocorasick.Match
+	found := false
+	matcher.Scan(line, func(match ahocorasick.Match) {
+		if !found {
+			first, last, found = match, match, true
+			return
+		}
+		if match.Start < first.Start || (match.Start == first.Start && match.End > first.End) {
+			first = match
+		}
+		if match.Start > last.Start || (match.Start == last.Start && match.End > last.End) {
+			last = match
+		}
+	})
+	return first, last, found
+}
 
-		// Add to sum.
-		sum += 10*firstInt + lastInt
-	}
+func concatenate(first int, last int) int {
+	// Write the values next to each other, which is 10*first + last for digits.
+	value, _ := strconv.Atoi(strconv.Itoa(first) + strconv.Itoa(last))
+	return value
+}
 
-	fmt.Printf("Total: %d\n", sum)
+// Calibration value recovered from a single line. Positions count characters
+// from zero and are -1 when nothing matched.
+type calibration struct {
+	Part          int    `json:"part"`
+	Line          int    `json:"line"`
+	Text          string `json:"text"`
+	First         string `json:"first"`
+	FirstPosition int    `json:"firstPosition"`
+	Last          string `json:"last"`
+	LastPosition  int    `json:"lastPosition"`
+	Value         int    `json:"value"`
+	Anomaly       string `json:"anomaly,omitempty"`
 }
 
-func part2(path string) {
+func calibrate(path string, vocab vocabulary, part int) []*calibration {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
-	// Compile regex.
-	forwardRegex := regexp.MustCompile("zero|one|two|three|four|five|six|seven|eight|nine|[0-9]")
-	backwardRegex := regexp.MustCompile("enin|thgie|neves|xis|evif|ruof|eerht|owt|eno|orez|[0-9]")
+	lookupTable := digits.merge(vocab)
+	matcher := lookupTable.matcher()
+	patterns := matcher.Patterns()
 
 	// Iterate per line.
+	calibrations := make([]*calibration, 0)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		entry := &calibration{Part: part, Line: lineNum, Text: line, FirstPosition: -1, LastPosition: -1}
+		calibrations = append(calibrations, entry)
+
+		// Grab first and last number.
+		first, last, found := firstAndLast(matcher, line)
+		if !found {
+			// Nothing to contribute, but worth pointing out.
+			entry.Anomaly = "no digits"
+			continue
+		}
+
+		entry.First = patterns[first.Pattern]
+		entry.FirstPosition = utf8.RuneCountInString(line[:first.Start])
+		entry.Last = patterns[last.Pattern]
+		entry.LastPosition = utf8.RuneCountInString(line[:last.Start])
+		entry.Value = concatenate(lookupTable[entry.First], lookupTable[entry.Last])
+	}
+
+	return calibrations
+}
+
+func calibrationSum(calibrations []*calibration) int {
 	sum := 0
-	for scanner.Scan() {
-		value := scanner.Text()
+	for _, entry := range calibrations {
+		sum += entry.Value
+	}
+	return sum
+}
 
-		// Grab first number.
-		first := forwardRegex.FindString(value)
-		firstInt := lookupTable[first]
+// Per line breakdown of the calibration values.
+type calibrationReport struct {
+	entries []*calibration
+}
 
-		// Grab last number
-		last := reverse(backwardRegex.FindString(reverse(value)))
-		lastInt := lookupTable[last]
+func (report *calibrationReport) add(calibrations []*calibration) {
+	report.entries = append(report.entries, calibrations...)
+}
 
-		// Add to sum.
-		sum += 10*firstInt + lastInt
+func (report *calibrationReport) print(format string) {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		utilities.ErrorCheck(encoder.Encode(report.entries))
+	case "table":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "Part\tLine\tFirst\tPosition\tLast\tPosition\tValue\tAnomaly")
+		for _, entry := range report.entries {
+			fmt.Fprintf(writer, "%d\t%d\t%s\t%d\t%s\t%d\t%d\t%s\n", entry.Part, entry.Line,
+				entry.First, entry.FirstPosition, entry.Last, entry.LastPosition, entry.Value, entry.Anomaly)
+		}
+		writer.Flush()
+	default:
+		fmt.Printf("Unknown report format: %s\n", format)
 	}
+}
+
+func part1(path string, report *calibrationReport) {
+	calibrations := calibrate(path, vocabulary{}, 1)
+	report.add(calibrations)
+	fmt.Printf("Total: %d\n", calibrationSum(calibrations))
+}
 
-	fmt.Printf("Total: %d\n", sum)
+func part2(path string, vocab vocabulary, report *calibrationReport) {
+	calibrations := calibrate(path, vocab, 2)
+	report.add(calibrations)
+	fmt.Printf("Total: %d\n", calibrationSum(calibrations))
 }
 
 func main() {
+	vocabName := flag.String("vocab", "english", "registered vocabulary name or a file of \"word value\" lines")
+	reportFormat := flag.String("report", "", "print a per line breakdown as table or json")
+	flag.Parse()
+
+	vocab, err := loadVocabulary(*vocabName)
+	utilities.ErrorCheck(err)
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
-	part2(path)
+	path := flag.Arg(0)
+	report := new(calibrationReport)
+	part1(path, report)
+	part2(path, vocab, report)
+
+	if *reportFormat != "" {
+		report.print(*reportFormat)
+	}
 }
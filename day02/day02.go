@@ -1,146 +1,415 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
 
-const redMax = 12
-const greenMax = 13
-const blueMax = 14
+// Limits used by the puzzle when none are given.
+const defaultBag = "red=12,green=13,blue=14"
+
+// Count of cubes per color. Colors that aren't present count as zero.
+type bag map[string]int
 
 type game struct {
 	id     int
-	rounds []*round
+	rounds []bag
 }
 
-type round struct {
-	red   int
-	green int
-	blue  int
+// *********************************************** //
+// Tokenizer and parser for the game line grammar. //
+// *********************************************** //
+
+// game  = "Game" number ":" round { ";" round }
+// round = entry { "," entry }
+// entry = number color
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenWord
+	tokenColon
+	tokenComma
+	tokenSemicolon
+	tokenEnd
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenNumber:    "number",
+	tokenWord:      "word",
+	tokenColon:     "':'",
+	tokenComma:     "','",
+	tokenSemicolon: "';'",
+	tokenEnd:       "end of line",
 }
 
-func newGame(str string) *game {
-	g := new(game)
+// Token along with the column it starts at, counting from one.
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
 
-	// Parse id.
-	idStr, str, _ := strings.Cut(str, ":")
-	fmt.Sscanf(idStr[5:], "%d", &g.id)
+func (t token) String() string {
+	if t.kind == tokenNumber || t.kind == tokenWord {
+		return fmt.Sprintf("%s %q", tokenNames[t.kind], t.text)
+	}
+	return tokenNames[t.kind]
+}
 
-	// Parse rounds.
-	rounds := strings.Split(str, ";")
-	for _, rStr := range rounds {
-		r := newRound(rStr)
-		g.rounds = append(g.rounds, r)
+var punctuation = map[rune]tokenKind{
+	':': tokenColon,
+	',': tokenComma,
+	';': tokenSemicolon,
+}
+
+func tokenize(line string) ([]token, error) {
+	runes := []rune(line)
+	tokens := make([]token, 0)
+	for i := 0; i < len(runes); {
+		char := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(char):
+			i++
+			continue
+		case unicode.IsDigit(char):
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i]), start + 1})
+		case unicode.IsLetter(char):
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[start:i]), start + 1})
+		default:
+			kind, ok := punctuation[char]
+			if !ok {
+				return nil, fmt.Errorf("column %d: unexpected character %q", start+1, char)
+			}
+			i++
+			tokens = append(tokens, token{kind, string(char), start + 1})
+		}
 	}
+	return append(tokens, token{tokenEnd, "", len(runes) + 1}), nil
+}
 
-	return g
+type gameParser struct {
+	tokens []token
+	pos    int
 }
 
-func newRound(str string) *round {
-	r := new(round)
+func (p *gameParser) peek() token {
+	return p.tokens[p.pos]
+}
 
-	// Parse colors.
-	colors := strings.Split(str, ",")
-	for _, colorStr := range colors {
-		var count int
-		var color string
-		fmt.Sscanf(colorStr, "%d %s", &count, &color)
-		r.addColor(color, count)
+// Consume the next token if it has the expected kind.
+func (p *gameParser) expect(kind tokenKind, what string) (token, error) {
+	next := p.peek()
+	if next.kind != kind {
+		return next, fmt.Errorf("column %d: expected %s, found %s", next.column, what, next)
 	}
+	p.pos++
+	return next, nil
+}
 
-	return r
+func (p *gameParser) number(what string) (int, error) {
+	tok, err := p.expect(tokenNumber, what)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return 0, fmt.Errorf("column %d: %s %s is out of range", tok.column, what, tok.text)
+	}
+	return value, nil
 }
 
-func (g *game) valid() bool {
-	// Check if all the rounds are valid.
-	for _, r := range g.rounds {
-		if !r.valid() {
-			return false
+func parseGame(line string) (*game, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+	p := &gameParser{tokens: tokens}
+
+	keyword, err := p.expect(tokenWord, "\"Game\"")
+	if err != nil {
+		return nil, err
+	}
+	if keyword.text != "Game" {
+		return nil, fmt.Errorf("column %d: expected \"Game\", found %s", keyword.column, keyword)
+	}
+
+	g := new(game)
+	if g.id, err = p.number("game id"); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenColon, "':' after the game id"); err != nil {
+		return nil, err
+	}
+
+	for {
+		r, err := p.round()
+		if err != nil {
+			return nil, err
 		}
+		g.rounds = append(g.rounds, r)
+
+		if p.peek().kind != tokenSemicolon {
+			break
+		}
+		p.pos++
 	}
-	return true
+
+	if _, err := p.expect(tokenEnd, "',', ';' or end of line"); err != nil {
+		return nil, err
+	}
+	return g, nil
 }
 
-func (g *game) max() *round {
-	// Compute fewest of each color required.
-	m := new(round)
-	for _, r := range g.rounds {
-		if r.red > m.red {
-			m.red = r.red
+func (p *gameParser) round() (bag, error) {
+	// Any color is accepted, even ones the bag doesn't have.
+	r := make(bag)
+	for {
+		count, err := p.number("cube count")
+		if err != nil {
+			return nil, err
 		}
-		if r.green > m.green {
-			m.green = r.green
+		color, err := p.expect(tokenWord, "color")
+		if err != nil {
+			return nil, err
 		}
-		if r.blue > m.blue {
-			m.blue = r.blue
+		if _, ok := r[color.text]; ok {
+			return nil, fmt.Errorf("column %d: color %q repeated in the same round", color.column, color.text)
 		}
+		r[color.text] = count
+
+		if p.peek().kind != tokenComma {
+			return r, nil
+		}
+		p.pos++
 	}
-	return m
 }
 
-func (r *round) power() int {
-	return r.red * r.green * r.blue
+func parseGames(path string) ([]*game, error) {
+	scanner, file := utilities.OpenFile(path)
+	defer file.Close()
+
+	var games []*game
+	var errs []error
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		g, err := parseGame(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", lineNum, err))
+			continue
+		}
+		games = append(games, g)
+	}
+
+	return games, errors.Join(errs...)
 }
 
-func (r *round) valid() bool {
-	// Check if a round is valid.
-	return r.red <= redMax && r.green <= greenMax && r.blue <= blueMax
+// Parse limits written as "color=count" pairs separated by commas.
+func parseBag(str string) (bag, error) {
+	b := make(bag)
+	for _, pair := range strings.Split(str, ",") {
+		color, countStr, found := strings.Cut(strings.TrimSpace(pair), "=")
+		count, err := strconv.Atoi(countStr)
+		if !found || color == "" || err != nil {
+			return nil, fmt.Errorf("invalid bag entry %q, expected color=count", pair)
+		}
+		b[color] = count
+	}
+	return b, nil
 }
 
-func (g *round) addColor(color string, count int) {
-	switch color {
-	case "red":
-		g.red += count
-	case "green":
-		g.green += count
-	case "blue":
-		g.blue += count
+// Colors in a stable order.
+func (b bag) colors() []string {
+	colors := make([]string, 0)
+	for color := range b {
+		colors = append(colors, color)
 	}
+	slices.Sort(colors)
+	return colors
 }
 
-func part1(path string) {
-	scanner, file := utilities.OpenFile(path)
-	defer file.Close()
+func (b bag) String() string {
+	pairs := make([]string, 0)
+	for _, color := range b.colors() {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", color, b[color]))
+	}
+	return strings.Join(pairs, ",")
+}
 
-	// Parse input.
-	sum := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		g := newGame(line)
+func (b bag) merge(other bag) bag {
+	// Keep the larger count of each color.
+	merged := make(bag)
+	for color, count := range b {
+		merged[color] = count
+	}
+	for color, count := range other {
+		merged[color] = max(merged[color], count)
+	}
+	return merged
+}
+
+func (b bag) add(other bag) bag {
+	sum := make(bag)
+	for color, count := range b {
+		sum[color] = count
+	}
+	for color, count := range other {
+		sum[color] += count
+	}
+	return sum
+}
 
-		// Add valid game to sum.
-		if g.valid() {
-			sum += g.id
+// Check if every cube drawn could have come out of this bag.
+func (b bag) admits(draw bag) bool {
+	for color, count := range draw {
+		if count > b[color] {
+			return false
 		}
 	}
+	return true
+}
 
-	fmt.Printf("Total: %d\n", sum)
+// Product of the counts of the given colors.
+func (b bag) power(colors []string) int {
+	power := 1
+	for _, color := range colors {
+		power *= b[color]
+	}
+	return power
 }
 
-func part2(path string) {
-	scanner, file := utilities.OpenFile(path)
-	defer file.Close()
+// Fewest cubes of each color that make the game possible.
+func (g *game) minimumBag() bag {
+	m := make(bag)
+	for _, r := range g.rounds {
+		m = m.merge(r)
+	}
+	return m
+}
 
-	// Parse input.
+// *************************************** //
+// Queries over a collection of the games. //
+// *************************************** //
+
+// Fewest cubes of each color that make all the games possible.
+func minimumBag(games []*game) bag {
+	m := make(bag)
+	for _, g := range games {
+		m = m.merge(g.minimumBag())
+	}
+	return m
+}
+
+// Games that are possible with the given bag.
+func feasible(games []*game, limits bag) []*game {
+	possible := make([]*game, 0)
+	for _, g := range games {
+		if limits.admits(g.minimumBag()) {
+			possible = append(possible, g)
+		}
+	}
+	return possible
+}
+
+// Games that are only possible once the bag gains the extra cubes.
+func newlyFeasible(games []*game, limits bag, extra bag) []*game {
+	before := feasible(games, limits)
+	after := feasible(games, limits.add(extra))
+	return slices.DeleteFunc(after, func(g *game) bool {
+		return slices.Contains(before, g)
+	})
+}
+
+// Games with ids in the inclusive range written as "X..Y".
+func gamesInRange(games []*game, str string) ([]*game, error) {
+	fromStr, toStr, found := strings.Cut(str, "..")
+	from, fromErr := strconv.Atoi(fromStr)
+	to, toErr := strconv.Atoi(toStr)
+	if !found || fromErr != nil || toErr != nil {
+		return nil, fmt.Errorf("invalid game range %q, expected X..Y", str)
+	}
+
+	selected := make([]*game, 0)
+	for _, g := range games {
+		if g.id >= from && g.id <= to {
+			selected = append(selected, g)
+		}
+	}
+	return selected, nil
+}
+
+func ids(games []*game) []int {
+	ids := make([]int, 0)
+	for _, g := range games {
+		ids = append(ids, g.id)
+	}
+	return ids
+}
+
+func part1(games []*game, limits bag) {
+	// Add ids of the possible games.
 	sum := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		power := newGame(line).max().power()
+	for _, g := range feasible(games, limits) {
+		sum += g.id
+	}
 
-		// Add powers together.
-		sum += power
+	fmt.Printf("Total: %d\n", sum)
+}
+
+func part2(games []*game, limits bag) {
+	// Add powers of the minimum bags over the colors being limited.
+	sum := 0
+	for _, g := range games {
+		sum += g.minimumBag().power(limits.colors())
 	}
 
 	fmt.Printf("Total: %d\n", sum)
 }
 
 func main() {
+	bagStr := flag.String("bag", defaultBag, "bag limits as color=count pairs")
+	admitRange := flag.String("admit", "", "print the minimum bag that admits games X..Y")
+	increase := flag.String("increase", "", "print games that become possible with the extra color=count cubes")
+	flag.Parse()
+
+	limits, err := parseBag(*bagStr)
+	utilities.ErrorCheck(err)
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
-	part2(path)
+	path := flag.Arg(0)
+	games, err := parseGames(path)
+	utilities.ErrorCheck(err)
+	part1(games, limits)
+	part2(games, limits)
+
+	if *admitRange != "" {
+		selected, err := gamesInRange(games, *admitRange)
+		utilities.ErrorCheck(err)
+		fmt.Printf("Minimum bag for games %s: %s\n", *admitRange, minimumBag(selected))
+	}
+
+	if *increase != "" {
+		extra, err := parseBag(*increase)
+		utilities.ErrorCheck(err)
+		fmt.Printf("Newly possible with %s: %v\n", extra, ids(newlyFeasible(games, limits, extra)))
+	}
 }
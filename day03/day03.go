@@ -1,185 +1,172 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"os"
-	"regexp"
-	"strconv"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
 
-var regex = regexp.MustCompile("[0-9]+")
-
 type schematic struct {
-	gridNumbers []*gridNumber
-	grid        [][]rune
-	gears       map[coordinate]*gear
-}
-
-type gridNumber struct {
-	value    int
-	row      int
-	colStart int
-	colEnd   int
-}
-
-type gridBlock struct {
-	origin *coordinate
-	block  [][]rune
+	grid    *utilities.Grid[rune]
+	numbers []*utilities.NumberSpan
 }
 
-type coordinate struct {
-	col, row int
+type symbol struct {
+	kind  rune
+	coord utilities.Coordinates
 }
 
-type gear struct {
-	parts []*gridNumber
+// Adjacency between numbers and symbols in both directions.
+type schematicIndex struct {
+	numbers         []*utilities.NumberSpan
+	symbols         []*symbol
+	symbolsByNumber map[*utilities.NumberSpan][]*symbol
+	numbersBySymbol map[*symbol][]*utilities.NumberSpan
 }
 
-func newSchematic() *schematic {
-	s := new(schematic)
-	s.gears = make(map[coordinate]*gear)
-	return s
+func isSymbol(val rune) bool {
+	return val != '.' && !unicode.IsDigit(val)
 }
 
-func newGridBlock() *gridBlock {
-	b := new(gridBlock)
-	b.origin = new(coordinate)
-	return b
-}
+func (s *schematic) index() *schematicIndex {
+	idx := new(schematicIndex)
+	idx.numbers = s.numbers
+	idx.symbols = make([]*symbol, 0)
+	idx.symbolsByNumber = make(map[*utilities.NumberSpan][]*symbol)
+	idx.numbersBySymbol = make(map[*symbol][]*utilities.NumberSpan)
 
-func (s *schematic) addLine(line string, lineNum int) {
-	// Parse numbers and add to list.
-	locs := regex.FindAllStringIndex(line, -1)
-	for _, loc := range locs {
-		gridNum := new(gridNumber)
-		gridNum.row = lineNum
-		gridNum.value, _ = strconv.Atoi(line[loc[0]:loc[1]])
-		gridNum.colStart = loc[0]
-		gridNum.colEnd = loc[1]
-		s.gridNumbers = append(s.gridNumbers, gridNum)
+	// Every symbol gets an entry, even if no numbers touch it.
+	symbols := make(map[utilities.Coordinates]*symbol)
+	for row, line := range s.grid.Data {
+		for col, val := range line {
+			if isSymbol(val) {
+				sym := &symbol{kind: val, coord: utilities.Coordinates{Row: row, Col: col}}
+				symbols[sym.coord] = sym
+				idx.symbols = append(idx.symbols, sym)
+				idx.numbersBySymbol[sym] = make([]*utilities.NumberSpan, 0)
+			}
+		}
 	}
 
-	// Add line to grid.
-	gridLine := []rune(line)
-	s.grid = append(s.grid, gridLine)
-}
-
-func (s *schematic) partLookup() []*gridNumber {
-	// Find all parts in schematic.
-	parts := make([]*gridNumber, 0)
-	for _, gridNum := range s.gridNumbers {
-		if s.isPart(gridNum) {
-			parts = append(parts, gridNum)
+	// Link each number with every symbol around it.
+	for _, span := range s.numbers {
+		idx.symbolsByNumber[span] = make([]*symbol, 0)
+		for _, coord := range s.grid.Neighborhood(span.Bounds, 1) {
+			if sym, ok := symbols[coord]; ok {
+				idx.symbolsByNumber[span] = append(idx.symbolsByNumber[span], sym)
+				idx.numbersBySymbol[sym] = append(idx.numbersBySymbol[sym], span)
+			}
 		}
 	}
-	return parts
+
+	return idx
 }
 
-func (s *schematic) isPart(gridNum *gridNumber) bool {
-	// Check each element in block.
-	block := s.getBlock(gridNum)
-	foundSymbol := false
-	for rowIdx, row := range block.block {
-		for colIdx, val := range row {
-			// Check for symbol.
-			switch val {
-			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '.':
-				// Ignore these.
-			case '*':
-				// Found a gear, which is a symbol.
-				coord := coordinate{row: block.origin.row + rowIdx, col: block.origin.col + colIdx}
-				if g, ok := s.gears[coord]; ok {
-					g.parts = append(g.parts, gridNum)
-				} else {
-					g = new(gear)
-					g.parts = append(g.parts, gridNum)
-					s.gears[coord] = g
-				}
-				return true
-			default:
-				foundSymbol = true
-			}
+// *********************** //
+// Queries over the index. //
+// *********************** //
+
+// Numbers next to a symbol of any of the given kinds, or of any kind if none are given.
+func adjacentTo(idx *schematicIndex, kinds string) []*utilities.NumberSpan {
+	numbers := make([]*utilities.NumberSpan, 0)
+	for _, span := range idx.numbers {
+		matches := slices.ContainsFunc(idx.symbolsByNumber[span], func(sym *symbol) bool {
+			return kinds == "" || strings.ContainsRune(kinds, sym.kind)
+		})
+		if matches {
+			numbers = append(numbers, span)
 		}
 	}
-	return foundSymbol
+	return numbers
 }
 
-func (s *schematic) getBlock(gridNum *gridNumber) *gridBlock {
-	height, width := len(s.grid), len(s.grid[0])
-	startRow, endRow := gridNum.row-1, gridNum.row+2
-	startCol, endCol := gridNum.colStart-1, gridNum.colEnd+1
-
-	// Clamp to grid bounds.
-	startRow, endRow = max(0, startRow), min(height, endRow)
-	startCol, endCol = max(0, startCol), min(width, endCol)
-
-	// Slice grid.
-	block := newGridBlock()
-	block.origin.row, block.origin.col = startRow, startCol
-	for rowIdx := startRow; rowIdx < endRow; rowIdx++ {
-		row := s.grid[rowIdx][startCol:endCol]
-		block.block = append(block.block, row)
+// Symbols of a kind that touch exactly n numbers.
+func withParts(idx *schematicIndex, kind rune, n int) []*symbol {
+	symbols := make([]*symbol, 0)
+	for _, sym := range idx.symbols {
+		if sym.kind == kind && len(idx.numbersBySymbol[sym]) == n {
+			symbols = append(symbols, sym)
+		}
 	}
-
-	return block
+	return symbols
 }
 
-func (s *schematic) gearRatio(g *gear) int {
-	if len(g.parts) == 2 {
-		// Can only compute gear ratio if there are exactly two parts.
-		return g.parts[0].value * g.parts[1].value
+// Product of the numbers touching a symbol.
+func ratio(idx *schematicIndex, sym *symbol) int {
+	product := 1
+	for _, span := range idx.numbersBySymbol[sym] {
+		product *= span.Value
 	}
-
-	return 0
+	return product
 }
 
-func generateSchematic(path string) *schematic {
-	scanner, file := utilities.OpenFile(path)
-	defer file.Close()
-
-	// Iterate per line.
-	diagram := newSchematic()
-	lineNum := 0
-	for scanner.Scan() {
-		line := scanner.Text()
+func generateSchematic(path string, vertical bool) (*schematic, error) {
+	s := new(schematic)
+	s.grid = utilities.GridFromFile(path)
 
-		// Parse line.
-		diagram.addLine(line, lineNum)
-		lineNum++
+	var err error
+	s.numbers, err = utilities.NumberSpans(s.grid, vertical)
+	if err != nil {
+		return nil, err
 	}
-
-	return diagram
+	return s, nil
 }
 
-func part1(path string) {
-	diagram := generateSchematic(path)
-
+func part1(idx *schematicIndex) {
 	sum := 0
-	parts := diagram.partLookup()
-	for _, part := range parts {
-		sum += part.value
+	for _, part := range adjacentTo(idx, "") {
+		sum += part.Value
 	}
 
 	fmt.Printf("Total: %d\n", sum)
 }
 
-func part2(path string) {
-	diagram := generateSchematic(path)
-
+func part2(idx *schematicIndex) {
+	// Gears are stars next to exactly two parts.
 	sum := 0
-	diagram.partLookup()
-	for _, g := range diagram.gears {
-		sum += diagram.gearRatio(g)
+	for _, g := range withParts(idx, '*', 2) {
+		sum += ratio(idx, g)
 	}
 
 	fmt.Printf("Total: %d\n", sum)
 }
 
 func main() {
+	adjacent := flag.String("adjacent", "", "print numbers next to any of these symbols")
+	kind := flag.String("symbol", "*", "symbol to look for with -parts")
+	parts := flag.Int("parts", -1, "print symbols next to exactly this many numbers")
+	vertical := flag.Bool("vertical", false, "also read numbers top to bottom")
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
-	part2(path)
+	path := flag.Arg(0)
+	diagram, err := generateSchematic(path, *vertical)
+	utilities.ErrorCheck(err)
+	idx := diagram.index()
+	part1(idx)
+	part2(idx)
+
+	if *adjacent != "" {
+		values := make([]int, 0)
+		for _, span := range adjacentTo(idx, *adjacent) {
+			values = append(values, span.Value)
+		}
+		fmt.Printf("Next to %q: %v\n", *adjacent, values)
+	}
+
+	if *parts >= 0 {
+		symbolKind, _ := utf8.DecodeRuneInString(*kind)
+		for _, sym := range withParts(idx, symbolKind, *parts) {
+			values := make([]int, 0)
+			for _, span := range idx.numbersBySymbol[sym] {
+				values = append(values, span.Value)
+			}
+			fmt.Printf("%c at row %d col %d: %v\n", sym.kind, sym.coord.Row, sym.coord.Col, values)
+		}
+	}
 }
@@ -1,9 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
-	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -19,11 +20,6 @@ func parseSliceToInt(strSlice []string) []int {
 	return intSlice
 }
 
-func copySliceOfGames(games []*scratchoffGame) []*scratchoffGame {
-	copy := make([]*scratchoffGame, 0)
-	return append(copy, games...)
-}
-
 type scratchoffGame struct {
 	id          int
 	winningNums []int
@@ -68,7 +64,7 @@ func (game *scratchoffGame) computePoints() int {
 	return int(math.Pow(2, float64(game.numMatches-1)))
 }
 
-func part1(path string) {
+func parseGames(path string) []*scratchoffGame {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
@@ -78,6 +74,51 @@ func part1(path string) {
 		game := newScratchoffGame(line)
 		games = append(games, game)
 	}
+	return games
+}
+
+// Copies of each card by id. Cards win copies of the cards with the next ids, so
+// the ids must be unique and contiguous, though not necessarily in file order.
+func cardCopies(games []*scratchoffGame) (map[int]int, error) {
+	byId := make(map[int]*scratchoffGame)
+	ids := make([]int, 0)
+	for _, game := range games {
+		if _, ok := byId[game.id]; ok {
+			return nil, fmt.Errorf("card %d appears more than once", game.id)
+		}
+		byId[game.id] = game
+		ids = append(ids, game.id)
+	}
+	slices.Sort(ids)
+	for i := 1; i < len(ids); i++ {
+		if ids[i] != ids[i-1]+1 {
+			return nil, fmt.Errorf("card %d is missing", ids[i-1]+1)
+		}
+	}
+
+	// Every card starts with its original copy.
+	copies := make(map[int]int)
+	for _, id := range ids {
+		copies[id] = 1
+	}
+
+	// Cards only win copies of later cards, so one pass in id order is enough.
+	for _, id := range ids {
+		game := byId[id]
+		if id+game.numMatches > ids[len(ids)-1] {
+			return nil, fmt.Errorf("card %d wins copies past the last card", id)
+		}
+
+		for i := 1; i <= game.numMatches; i++ {
+			copies[id+i] += copies[id]
+		}
+	}
+
+	return copies, nil
+}
+
+func part1(path string) {
+	games := parseGames(path)
 
 	sum := 0
 	for _, game := range games {
@@ -87,41 +128,36 @@ func part1(path string) {
 	fmt.Printf("Total: %d\n", sum)
 }
 
-func part2(path string) {
-	scanner, file := utilities.OpenFile(path)
-	defer file.Close()
-
-	games := make([]*scratchoffGame, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		game := newScratchoffGame(line)
-		games = append(games, game)
-	}
+func part2(path string, showCopies bool) {
+	games := parseGames(path)
+	copies, err := cardCopies(games)
+	utilities.ErrorCheck(err)
 
-	// Continuously get game copies until there aren't any more.
 	count := 0
-	toProcess := copySliceOfGames(games)
-	for len(toProcess) != 0 {
-		// Dequeue toProcess.
-		game := toProcess[0]
-		toProcess = toProcess[1:]
-
-		// Add following games based on number of matches.
-		for i := 0; i < game.numMatches; i++ {
-			// Game id is one more than index, but next one after current is desired.
-			anotherGameIdx := game.id + i
-			toProcess = append(toProcess, games[anotherGameIdx])
-		}
-
-		count++
+	for _, numCopies := range copies {
+		count += numCopies
 	}
 
 	fmt.Printf("Count: %d\n", count)
+
+	if showCopies {
+		ids := make([]int, 0)
+		for id := range copies {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			fmt.Printf("Card %d: %d\n", id, copies[id])
+		}
+	}
 }
 
 func main() {
+	showCopies := flag.Bool("copies", false, "print how many copies of each card are won")
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
+	path := flag.Arg(0)
 	part1(path)
-	part2(path)
+	part2(path, *showCopies)
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"os"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -14,57 +16,151 @@ type boatRace struct {
 	recordDistance int
 }
 
-func makeBoatRaces(path string) []*boatRace {
+// Labelled rows of numbers from a race sheet.
+type raceSheet struct {
+	rows map[string][]string
+}
+
+func parseRaceSheet(path string) (*raceSheet, error) {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
-	// Get race times.
-	scanner.Scan()
-	timeLine := scanner.Text()[9:]
+	sheet := new(raceSheet)
+	sheet.rows = make(map[string][]string)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Split the label from the numbers.
+		label, values, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("line %d: missing label", lineNum)
+		}
+		label = strings.ToLower(strings.TrimSpace(label))
+		if _, ok := sheet.rows[label]; ok {
+			return nil, fmt.Errorf("line %d: duplicate row %q", lineNum, label)
+		}
+
+		fields := strings.Fields(values)
+		for _, field := range fields {
+			if _, err := strconv.Atoi(field); err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", lineNum, field)
+			}
+		}
+		sheet.rows[label] = fields
+	}
+
+	// Both rows are required and must line up.
+	times, distances, err := sheet.columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(times) != len(distances) {
+		return nil, fmt.Errorf("%d times but %d distances", len(times), len(distances))
+	}
 
-	// Get distance records.
-	scanner.Scan()
-	distanceLine := scanner.Text()[9:]
+	return sheet, nil
+}
 
-	// Parse numbers.
-	times := utilities.StringsToInts(strings.Fields(timeLine))
-	distances := utilities.StringsToInts(strings.Fields(distanceLine))
+func (sheet *raceSheet) columns() ([]string, []string, error) {
+	times, ok := sheet.rows["time"]
+	if !ok {
+		return nil, nil, errors.New("missing time row")
+	}
+	distances, ok := sheet.rows["distance"]
+	if !ok {
+		return nil, nil, errors.New("missing distance row")
+	}
+	return times, distances, nil
+}
+
+// Each column is a separate race.
+func (sheet *raceSheet) races() []*boatRace {
+	times, distances, _ := sheet.columns()
 
 	var boatRaces []*boatRace
 	for i := 0; i < len(times); i++ {
 		race := new(boatRace)
-		race.time = times[i]
-		race.recordDistance = distances[i]
+		race.time, _ = strconv.Atoi(times[i])
+		race.recordDistance, _ = strconv.Atoi(distances[i])
 		boatRaces = append(boatRaces, race)
 	}
 
 	return boatRaces
 }
 
-func makeBoatRace(path string) *boatRace {
-	scanner, file := utilities.OpenFile(path)
-	defer file.Close()
+// Bad kerning, so all columns are a single race.
+func (sheet *raceSheet) kernedRace() (*boatRace, error) {
+	times, distances, _ := sheet.columns()
 
-	// Get race times.
-	scanner.Scan()
-	timeLine := scanner.Text()[9:]
+	var err error
+	race := new(boatRace)
+	race.time, err = strconv.Atoi(strings.Join(times, ""))
+	if err != nil {
+		return nil, fmt.Errorf("kerned time: %w", err)
+	}
+	race.recordDistance, err = strconv.Atoi(strings.Join(distances, ""))
+	if err != nil {
+		return nil, fmt.Errorf("kerned distance: %w", err)
+	}
 
-	// Get distance records.
-	scanner.Scan()
-	distanceLine := scanner.Text()[9:]
+	return race, nil
+}
 
-	// Parse numbers.
-	times, _ := strconv.Atoi(strings.ReplaceAll(timeLine, " ", ""))
-	distances, _ := strconv.Atoi(strings.ReplaceAll(distanceLine, " ", ""))
+func (race *boatRace) waysToWin() int {
+	// Winning velocities satisfy v*(t-v) > d, or v^2 - t*v + d < 0, which holds
+	// strictly between the roots (t +- sqrt(t^2 - 4d)) / 2. Work in math/big so
+	// t^2 can't overflow.
+	t := big.NewInt(int64(race.time))
+	d := big.NewInt(int64(race.recordDistance))
+	discriminant := new(big.Int).Mul(t, t)
+	discriminant.Sub(discriminant, new(big.Int).Lsh(d, 2))
+	if discriminant.Sign() < 0 {
+		// Never beats the record.
+		return 0
+	}
 
-	race := new(boatRace)
-	race.time = times
-	race.recordDistance = distances
+	// Integer square root gives the lower root to within one, so nudge it onto the
+	// exact boundary instead of trusting float rounding.
+	root := new(big.Int).Sqrt(discriminant)
+	low := new(big.Int).Sub(t, root)
+	low.Rsh(low, 1)
+	half := new(big.Int).Rsh(t, 1)
+	one := big.NewInt(1)
+	for low.Cmp(half) <= 0 && !race.wins(low) {
+		low.Add(low, one)
+	}
+	if low.Cmp(half) > 0 {
+		// Even the best velocity loses.
+		return 0
+	}
+	for low.Cmp(one) > 0 && race.wins(new(big.Int).Sub(low, one)) {
+		low.Sub(low, one)
+	}
 
-	return race
+	// Only velocities that leave some time to move count, and winners are
+	// symmetric about t/2.
+	if low.Cmp(one) < 0 {
+		low.Set(one)
+	}
+	high := new(big.Int).Sub(t, low)
+	count := high.Sub(high, low).Add(high, one)
+	if count.Sign() < 0 {
+		return 0
+	}
+	return int(count.Int64())
 }
 
-func (race *boatRace) waysToWin() int {
+func (race *boatRace) wins(velocity *big.Int) bool {
+	// Check if holding the button for some time beats the record.
+	timeLeft := new(big.Int).Sub(big.NewInt(int64(race.time)), velocity)
+	totalDistance := timeLeft.Mul(timeLeft, velocity)
+	return totalDistance.Cmp(big.NewInt(int64(race.recordDistance))) > 0
+}
+
+func (race *boatRace) waysToWinBruteForce() int {
 	// Compute all the ways to win the race.
 	wins := 0
 	for velocity := 1; velocity < race.time; velocity++ {
@@ -77,26 +173,33 @@ func (race *boatRace) waysToWin() int {
 	return wins
 }
 
-func part1(path string) {
-	boatRaces := makeBoatRaces(path)
-	product := 1
+func part1(sheet *raceSheet) {
+	boatRaces := sheet.races()
+	product := utilities.NewNumber(1)
 	for _, race := range boatRaces {
-		product *= race.waysToWin()
+		product = product.Multiply(utilities.NewNumber(race.waysToWin()))
 	}
 
-	fmt.Printf("Product: %d\n", product)
+	fmt.Printf("Product: %v\n", product)
 }
 
-func part2(path string) {
-	boatRace := makeBoatRace(path)
+func part2(sheet *raceSheet) {
+	boatRace, err := sheet.kernedRace()
+	utilities.ErrorCheck(err)
 	ways := boatRace.waysToWin()
 
 	fmt.Printf("Ways to Win: %d\n", ways)
 }
 
 func main() {
+	utilities.BigIntFlag()
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
-	part2(path)
+	path := flag.Arg(0)
+	sheet, err := parseRaceSheet(path)
+	utilities.ErrorCheck(err)
+
+	part1(sheet)
+	part2(sheet)
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestWaysToWin(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     int
+		distance int
+		want     int
+	}{
+		{"example race 1", 7, 9, 4},
+		{"example race 2", 15, 40, 8},
+		{"example race 3", 30, 200, 9},
+		{"example kerned race", 71530, 940200, 71503},
+		{"no time", 0, 0, 0},
+		{"no time with negative record", 0, -5, 0},
+		{"negative record", 10, -1, 9},
+		{"zero discriminant", 10, 25, 0},
+		{"zero discriminant small", 4, 4, 0},
+		{"negative discriminant", 10, 26, 0},
+		{"beyond float precision", 9007199254740993, 1000000000000000000, 9007199254740770},
+	}
+
+	for _, test := range tests {
+		race := &boatRace{time: test.time, recordDistance: test.distance}
+		if got := race.waysToWin(); got != test.want {
+			t.Errorf("%s: waysToWin(%d, %d) = %d, want %d", test.name, test.time, test.distance, got, test.want)
+		}
+	}
+}
+
+func TestWaysToWinMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(2023))
+	for i := 0; i < 5000; i++ {
+		race := new(boatRace)
+		race.time = rng.Intn(1000)
+		race.recordDistance = rng.Intn(race.time*race.time/4+10) - 5
+
+		if got, want := race.waysToWin(), race.waysToWinBruteForce(); got != want {
+			t.Fatalf("waysToWin(%d, %d) = %d, brute force found %d", race.time, race.recordDistance, got, want)
+		}
+	}
+}
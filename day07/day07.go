@@ -1,9 +1,15 @@
 package main
 
 import (
+	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
@@ -19,61 +25,175 @@ const (
 	fiveKind
 )
 
-var cardValues = map[rune]int{
-	'2': 0,
-	'3': 1,
-	'4': 2,
-	'5': 3,
-	'6': 4,
-	'7': 5,
-	'8': 6,
-	'9': 7,
-	'T': 8,
-	'J': 9,
-	'Q': 10,
-	'K': 11,
-	'A': 12,
+// Names of five card hand types.
+var handTypeNames = map[int]string{
+	highCard:  "High Card",
+	onePair:   "One Pair",
+	twoPair:   "Two Pair",
+	threeKind: "Three of a Kind",
+	fullHouse: "Full House",
+	fourKind:  "Four of a Kind",
+	fiveKind:  "Five of a Kind",
 }
 
+// Card orderings from lowest to highest.
+const (
+	standardOrdering = "23456789TJQKA"
+	jokerOrdering    = "J23456789TQKA"
+)
+
 type camelHand struct {
-	cards    string
-	bid      int
-	handType int
+	cards      string
+	bid        int
+	handType   int
+	substitute rune
+}
+
+// Scores hands for a card ordering, set of wildcards and hand size.
+type handEvaluator struct {
+	ranks      map[rune]int
+	wildcards  string
+	handSize   int
+	handTypes  map[string]int
+	signatures [][]int
+}
+
+func newHandEvaluator(ordering string, wildcards string, handSize int) *handEvaluator {
+	evaluator := new(handEvaluator)
+	evaluator.wildcards = wildcards
+	evaluator.handSize = handSize
+
+	evaluator.ranks = make(map[rune]int)
+	for rank, card := range ordering {
+		evaluator.ranks[card] = rank
+	}
+
+	// Hand types are count signatures ordered lexicographically, which for five
+	// cards runs from high card up to five of a kind.
+	evaluator.handTypes = make(map[string]int)
+	evaluator.signatures = partitions(handSize, handSize)
+	for handType, signature := range evaluator.signatures {
+		evaluator.handTypes[signatureKey(signature)] = handType
+	}
+
+	return evaluator
+}
+
+func partitions(total int, largest int) [][]int {
+	// All ways to split total into parts no bigger than largest, in decreasing
+	// order within each split. Returned in increasing lexicographic order.
+	if total == 0 {
+		return [][]int{{}}
+	}
+
+	result := make([][]int, 0)
+	for part := 1; part <= min(total, largest); part++ {
+		for _, rest := range partitions(total-part, part) {
+			result = append(result, append([]int{part}, rest...))
+		}
+	}
+	return result
+}
+
+func signatureKey(signature []int) string {
+	return fmt.Sprint(signature)
+}
+
+// Determine the hand type along with the card any wildcards stand in for.
+func (evaluator *handEvaluator) evaluate(cards string) (int, rune, error) {
+	if len([]rune(cards)) != evaluator.handSize {
+		return 0, 0, fmt.Errorf("hand %q has %d cards, expected %d", cards, len([]rune(cards)), evaluator.handSize)
+	}
+
+	// Count each card, keeping wildcards separate.
+	counts := make(map[rune]int)
+	wildCount := 0
+	for _, card := range cards {
+		if _, ok := evaluator.ranks[card]; !ok {
+			return 0, 0, fmt.Errorf("hand %q has unknown card %q", cards, card)
+		}
+
+		if strings.ContainsRune(evaluator.wildcards, card) {
+			wildCount++
+		} else {
+			counts[card]++
+		}
+	}
+
+	if wildCount == 0 {
+		return evaluator.handTypes[signature(counts)], 0, nil
+	}
+
+	// Pointing every wildcard at the same card is always best, so try each card
+	// already in the hand. A hand of only wildcards uses the highest card.
+	candidates := make([]rune, 0)
+	for card := range counts {
+		candidates = append(candidates, card)
+	}
+	if len(candidates) == 0 {
+		candidates = append(candidates, evaluator.highestCard())
+	}
+
+	bestType, bestCard := -1, rune(0)
+	for _, card := range candidates {
+		counts[card] += wildCount
+		handType := evaluator.handTypes[signature(counts)]
+		counts[card] -= wildCount
+
+		better := handType > bestType
+		tied := handType == bestType && evaluator.ranks[card] > evaluator.ranks[bestCard]
+		if better || tied {
+			bestType, bestCard = handType, card
+		}
+	}
+
+	return bestType, bestCard, nil
+}
+
+func (evaluator *handEvaluator) highestCard() rune {
+	// Highest ranked card that isn't a wildcard.
+	best, bestRank := rune(0), -1
+	for card, rank := range evaluator.ranks {
+		if !strings.ContainsRune(evaluator.wildcards, card) && rank > bestRank {
+			best, bestRank = card, rank
+		}
+	}
+	return best
 }
 
-func compareCamelHandsNoWildcard(a, b *camelHand) int {
-	return compareCamelHands(a, b, false)
+func signature(counts map[rune]int) string {
+	// Card counts sorted from most to least common.
+	values := make([]int, 0)
+	for _, count := range counts {
+		if count > 0 {
+			values = append(values, count)
+		}
+	}
+	slices.Sort(values)
+	slices.Reverse(values)
+	return signatureKey(values)
 }
 
-func compareCamelHandsWildcard(a, b *camelHand) int {
-	return compareCamelHands(a, b, true)
+func (evaluator *handEvaluator) typeName(handType int) string {
+	// Only five card hands have names, otherwise show the count signature.
+	if name, ok := handTypeNames[handType]; ok && evaluator.handSize == 5 {
+		return name
+	}
+	return signatureKey(evaluator.signatures[handType])
 }
 
-func compareCamelHands(a, b *camelHand, wildcard bool) int {
+func (evaluator *handEvaluator) compare(a, b *camelHand) int {
 	// Sort by hand type.
 	if a.handType != b.handType {
 		return a.handType - b.handType
 	}
 
 	// Sort by card value if handTypes are the same.
-	for i := 0; i < len(a.cards); i++ {
-		if a.cards[i] != b.cards[i] {
+	cardsA, cardsB := []rune(a.cards), []rune(b.cards)
+	for i := range cardsA {
+		if cardsA[i] != cardsB[i] {
 			// Compare using this card.
-			valA := cardValues[rune(a.cards[i])]
-			valB := cardValues[rune(b.cards[i])]
-
-			if wildcard {
-				// Make jokers the lowest value.
-				if valA == cardValues['J'] {
-					valA = -1
-				}
-
-				if valB == cardValues['J'] {
-					valB = -1
-				}
-			}
-
-			return valA - valB
+			return evaluator.ranks[cardsA[i]] - evaluator.ranks[cardsB[i]]
 		}
 	}
 
@@ -90,124 +210,155 @@ func computeWinnings(hands []*camelHand) int {
 	return winnings
 }
 
-func computeRank(cards string, wildcard bool) int {
-	// Compute type of rank from the cards in a hand.
-	cardSet := map[rune]int{}
+// Table explaining how each hand was ranked.
+type rankingReport struct {
+	rows [][]string
+}
 
-	// Add each card to the set.
-	for _, card := range cards {
-		_, ok := cardSet[card]
-		if ok {
-			// Card in set already.
-			cardSet[card]++
-		} else {
-			cardSet[card] = 1
+var reportHeader = []string{"Part", "Hand", "Type", "Substitution", "Rank", "Bid", "Contribution"}
+
+func (report *rankingReport) add(part string, hands []*camelHand, evaluator *handEvaluator) {
+	// Hands must already be sorted.
+	for idx, hand := range hands {
+		substitution := "-"
+		if hand.substitute != 0 {
+			substitution = fmt.Sprintf("%s->%c", evaluator.wildcards, hand.substitute)
 		}
-	}
 
-	_, ok := cardSet['J']
-	if !ok {
-		// Don't do wildcard processing.
-		wildcard = false
+		rank := idx + 1
+		row := []string{
+			part,
+			hand.cards,
+			evaluator.typeName(hand.handType),
+			substitution,
+			strconv.Itoa(rank),
+			strconv.Itoa(hand.bid),
+			strconv.Itoa(rank * hand.bid),
+		}
+		report.rows = append(report.rows, row)
 	}
+}
 
-	// Hand type based on set's length.
-	switch len(cardSet) {
-	case 1:
-		// Five of a kind.
-		// No special case for all joker wildcards.
-		return fiveKind
-	case 2:
-		// Promote to five of a kind if wildcard.
-		if wildcard {
-			return fiveKind
-		}
-		// Four of a kind or full house.
-		for _, count := range cardSet {
-			if count == 4 {
-				// Four of a kind.
-				return fourKind
-			}
-		}
-		// Full house.
-		return fullHouse
-	case 3:
-		// Promote to three of a kind or full house if wildcard.
-		if wildcard {
-			// Only full house if one joker and two of each of the other cards.
-			if cardSet['J'] == 1 {
-				for key, value := range cardSet {
-					if key != 'J' && value == 2 {
-						return fullHouse
-					}
-				}
-			}
-			return fourKind
-		}
-		// Two pair or three of a kind.
-		for _, count := range cardSet {
-			if count == 3 {
-				// Three of a kind.
-				return threeKind
-			}
-		}
-		// Two pair.
-		return twoPair
-	case 4:
-		// Promote to three of a kind if wildcard.
-		if wildcard {
-			return threeKind
-		}
-		// One pair.
-		return onePair
-	case 5:
-		// Promote to pair if wildcard.
-		if wildcard {
-			return onePair
-		}
-		// High card.
-		return highCard
+func (report *rankingReport) print() {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(reportHeader, "\t"))
+	for _, row := range report.rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
 	}
+	writer.Flush()
+}
+
+func (report *rankingReport) writeCSV(path string) {
+	file, err := os.Create(path)
+	utilities.ErrorCheck(err)
+	defer file.Close()
 
-	// Invalid hand.
-	return -1
+	writer := csv.NewWriter(file)
+	writer.Write(reportHeader)
+	writer.WriteAll(report.rows)
+	utilities.ErrorCheck(writer.Error())
 }
 
-func parseHands(path string, wildcard bool) []*camelHand {
+func parseHands(path string, evaluator *handEvaluator) ([]*camelHand, error) {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
-	// Get race times.
 	var hands []*camelHand
-	for scanner.Scan() {
+	var errs []error
+	firstSeen := make(map[string]int)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
 		line := scanner.Text()
+		fields := strings.Fields(line)
+
+		// Expect a hand followed by a bid.
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			errs = append(errs, fmt.Errorf("line %d: hand %q is missing a bid", lineNum, fields[0]))
+			continue
+		case 2:
+			// Hand and bid.
+		default:
+			errs = append(errs, fmt.Errorf("line %d: expected a hand and a bid, found %d fields", lineNum, len(fields)))
+			continue
+		}
 
 		hand := new(camelHand)
+		hand.cards = fields[0]
+
+		// Report every card that isn't in the ordering.
+		invalid := make([]rune, 0)
+		for _, card := range hand.cards {
+			if _, ok := evaluator.ranks[card]; !ok && !slices.Contains(invalid, card) {
+				invalid = append(invalid, card)
+			}
+		}
+		if len(invalid) != 0 {
+			errs = append(errs, fmt.Errorf("line %d: hand %q has invalid cards %q", lineNum, hand.cards, string(invalid)))
+			continue
+		}
+
+		if prev, ok := firstSeen[hand.cards]; ok {
+			errs = append(errs, fmt.Errorf("line %d: duplicate hand %q, first seen on line %d", lineNum, hand.cards, prev))
+			continue
+		}
+		firstSeen[hand.cards] = lineNum
+
+		var err error
+		hand.bid, err = strconv.Atoi(fields[1])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: invalid bid %q", lineNum, fields[1]))
+			continue
+		}
+
+		hand.handType, hand.substitute, err = evaluator.evaluate(hand.cards)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", lineNum, err))
+			continue
+		}
+
 		hands = append(hands, hand)
-		fmt.Sscanf(line, "%s %d", &hand.cards, &hand.bid)
-		hand.handType = computeRank(hand.cards, wildcard)
 	}
 
-	return hands
+	return hands, errors.Join(errs...)
 }
 
-func part1(path string) {
-	hands := parseHands(path, false)
-	slices.SortFunc(hands, compareCamelHandsNoWildcard)
+func part1(path string, report *rankingReport) {
+	evaluator := newHandEvaluator(standardOrdering, "", 5)
+	hands, err := parseHands(path, evaluator)
+	utilities.ErrorCheck(err)
+	slices.SortFunc(hands, evaluator.compare)
+	report.add("1", hands, evaluator)
 	winnings := computeWinnings(hands)
 	fmt.Printf("Winnings: %d\n", winnings)
 }
 
-func part2(path string) {
-	hands := parseHands(path, true)
-	slices.SortFunc(hands, compareCamelHandsWildcard)
+func part2(path string, report *rankingReport) {
+	evaluator := newHandEvaluator(jokerOrdering, "J", 5)
+	hands, err := parseHands(path, evaluator)
+	utilities.ErrorCheck(err)
+	slices.SortFunc(hands, evaluator.compare)
+	report.add("2", hands, evaluator)
 	winnings := computeWinnings(hands)
 	fmt.Printf("Winnings: %d\n", winnings)
 }
 
 func main() {
+	explain := flag.Bool("explain", false, "print how every hand was ranked")
+	csvPath := flag.String("csv", "", "export the ranking table to a CSV file")
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
-	part2(path)
+	path := flag.Arg(0)
+	report := new(rankingReport)
+	part1(path, report)
+	part2(path, report)
+
+	if *explain {
+		report.print()
+	}
+	if *csvPath != "" {
+		report.writeCSV(*csvPath)
+	}
 }
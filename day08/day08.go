@@ -1,34 +1,29 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
+	"github.com/iSkytran/2023adventofcode/utilities/graph"
+	"github.com/iSkytran/2023adventofcode/utilities/numth"
 )
 
-func lcm(numSlice []int) int {
-	// Least common multiple of a list of numbers.
-	current := 1
-	for _, num := range numSlice {
-		current = num * current / gcd(num, current)
-	}
-	return current
-}
+var errNoCommonStep = errors.New("ghosts never reach an end on the same step")
 
-func gcd(a, b int) int {
-	// Euclidean algorithm.
-	if b == 0 {
-		return a
-	}
-	return gcd(b, a%b)
+type navigationMap struct {
+	instructions *instructionCursor
+	network      map[string]*networkNode
 }
 
-type navigationMap struct {
+// Position within the repeating list of instructions.
+type instructionCursor struct {
 	instructions []rune
-	network      map[string]*networkNode
+	index        int
 }
 
 type networkNode struct {
@@ -44,7 +39,7 @@ func parseMap(path string) *navigationMap {
 	navMap := new(navigationMap)
 
 	scanner.Scan()
-	navMap.instructions = []rune(scanner.Text())
+	navMap.instructions = newInstructionCursor([]rune(scanner.Text()))
 	navMap.network = make(map[string]*networkNode, 0)
 
 	scanner.Scan() // Ignore blank line.
@@ -63,7 +58,115 @@ func parseMap(path string) *navigationMap {
 	return navMap
 }
 
-func (navMap *navigationMap) stepsToExit(start string, end string) int {
+func newInstructionCursor(instructions []rune) *instructionCursor {
+	cursor := new(instructionCursor)
+	cursor.instructions = instructions
+	return cursor
+}
+
+func (cursor *instructionCursor) Index() int {
+	return cursor.index
+}
+
+func (cursor *instructionCursor) Next() rune {
+	// Return current instruction and wrap around at the end.
+	instruction := cursor.instructions[cursor.index]
+	cursor.index = (cursor.index + 1) % len(cursor.instructions)
+	return instruction
+}
+
+func (cursor *instructionCursor) Clone() *instructionCursor {
+	clone := *cursor
+	return &clone
+}
+
+func (navMap *navigationMap) toGraph() *graph.Graph[string] {
+	// Add nodes in sorted order so exports are stable.
+	names := make([]string, 0)
+	for name := range navMap.network {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	g := graph.NewDirected[string]()
+	for _, name := range names {
+		g.AddNode(name)
+	}
+	for _, name := range names {
+		node := navMap.network[name]
+		g.AddEdge(name, node.left, "L")
+		g.AddEdge(name, node.right, "R")
+	}
+	return g
+}
+
+// Path of a single ghost through the (node, instruction index) state space.
+type ghostWalk struct {
+	transientHits []int
+	cycleHits     []int
+	cycleStart    int
+	cycleLength   int
+}
+
+// A ghost's position along with where it is in the instructions.
+type walkState struct {
+	node  string
+	index int
+}
+
+func (navMap *navigationMap) analyzeGhost(start string, end string) *ghostWalk {
+	walk := new(ghostWalk)
+	seen := make(map[walkState]int)
+	hits := make([]int, 0)
+
+	// Every ghost follows the instructions from the beginning.
+	cursor := navMap.instructions.Clone()
+	current := start
+	for steps := 0; ; steps++ {
+		// Stop once a state repeats, since the walk is periodic from there on.
+		state := walkState{current, cursor.Index()}
+		if prev, found := seen[state]; found {
+			walk.cycleStart = prev
+			walk.cycleLength = steps - prev
+			break
+		}
+		seen[state] = steps
+
+		if strings.Contains(current, end) {
+			hits = append(hits, steps)
+		}
+
+		if cursor.Next() == 'L' {
+			// Move left.
+			current = navMap.network[current].left
+		} else {
+			// Move right.
+			current = navMap.network[current].right
+		}
+	}
+
+	// Split hits into those before the cycle and those inside it.
+	for _, hit := range hits {
+		if hit < walk.cycleStart {
+			walk.transientHits = append(walk.transientHits, hit)
+		} else {
+			walk.cycleHits = append(walk.cycleHits, hit)
+		}
+	}
+
+	return walk
+}
+
+func (walk *ghostWalk) atEnd(steps int) bool {
+	// Check if the ghost is on an end node after some number of steps.
+	if steps < walk.cycleStart {
+		return slices.Contains(walk.transientHits, steps)
+	}
+	position := walk.cycleStart + (steps-walk.cycleStart)%walk.cycleLength
+	return slices.Contains(walk.cycleHits, position)
+}
+
+func (navMap *navigationMap) stepsToExit(start string, end string) (int, error) {
 	// Find starts.
 	starts := make([]string, 0)
 	for key := range navMap.network {
@@ -74,53 +177,129 @@ func (navMap *navigationMap) stepsToExit(start string, end string) int {
 
 	// Invalid start string.
 	if len(starts) == 0 {
-		return 0
+		return 0, nil
 	}
 
-	allSteps := make([]int, 0)
+	// Map iteration order is random, so fix the order of the ghosts.
+	slices.Sort(starts)
+
+	walks := make([]*ghostWalk, 0)
+	latestCycleStart := 1
 	for _, current := range starts {
-		steps := 0
-		for {
-			instruction := navMap.instructions[0]
-			if instruction == 'L' {
-				// Move left.
-				current = navMap.network[current].left
-			} else {
-				// Move right.
-				current = navMap.network[current].right
-			}
+		walk := navMap.analyzeGhost(current, end)
+		walks = append(walks, walk)
+		latestCycleStart = max(latestCycleStart, walk.cycleStart)
+	}
+
+	// Before every ghost is cycling, check the first ghost's hits directly.
+	first := walks[0]
+	for steps := 1; steps < latestCycleStart; steps++ {
+		if !first.atEnd(steps) {
+			continue
+		}
+
+		allAtEnd := true
+		for _, walk := range walks[1:] {
+			allAtEnd = allAtEnd && walk.atEnd(steps)
+		}
+		if allAtEnd {
+			return steps, nil
+		}
+	}
 
-			// Move instruction to the end.
-			navMap.instructions = navMap.instructions[1:]
-			navMap.instructions = append(navMap.instructions, instruction)
-			steps++
+	// Once every ghost is cycling, combine each choice of hits in the cycles.
+	best, found := 0, false
+	err := combineHits(walks, nil, nil, func(hits []int, lengths []int) error {
+		steps, period, err := numth.CRT(hits, lengths)
+		if err == numth.ErrNoSolution {
+			return nil
+		} else if err != nil {
+			return err
+		}
 
-			// Break if end reached.
-			if strings.Contains(current, end) {
-				allSteps = append(allSteps, steps)
-				break
+		// Earliest matching step after all ghosts started cycling.
+		if steps < latestCycleStart {
+			numPeriods := (latestCycleStart - steps + period - 1) / period
+			shift, ok := numth.MulChecked(numPeriods, period)
+			if !ok {
+				return numth.ErrOverflow
 			}
+			steps += shift
 		}
+
+		if !found || steps < best {
+			best, found = steps, true
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, errNoCommonStep
+	}
+	return best, nil
+}
+
+func combineHits(walks []*ghostWalk, hits []int, lengths []int, visit func([]int, []int) error) error {
+	// Visit every way of picking one cycle hit per ghost.
+	if len(hits) == len(walks) {
+		return visit(hits, lengths)
 	}
 
-	return lcm(allSteps)
+	walk := walks[len(hits)]
+	for _, hit := range walk.cycleHits {
+		err := combineHits(walks, append(hits, hit), append(lengths, walk.cycleLength), visit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func part1(path string) {
 	navMap := parseMap(path)
-	steps := navMap.stepsToExit("AAA", "ZZZ")
+	steps, err := navMap.stepsToExit("AAA", "ZZZ")
+	if err != nil {
+		fmt.Printf("No Solution: %v\n", err)
+		return
+	}
 	fmt.Printf("Steps: %d\n", steps)
 }
 
 func part2(path string) {
 	navMap := parseMap(path)
-	steps := navMap.stepsToExit("A", "Z")
+	steps, err := navMap.stepsToExit("A", "Z")
+	if err != nil {
+		fmt.Printf("No Solution: %v\n", err)
+		return
+	}
 	fmt.Printf("Steps: %d\n", steps)
 }
 
+func exportNetwork(path string, format string) {
+	network := parseMap(path).toGraph()
+	switch format {
+	case "dot":
+		fmt.Print(network.ToDOT())
+	case "mermaid":
+		fmt.Print(network.ToMermaid())
+	default:
+		fmt.Printf("Unknown export format: %s\n", format)
+	}
+}
+
 func main() {
+	export := flag.String("export", "", "print the network as dot or mermaid instead of solving")
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
+	path := flag.Arg(0)
+	if *export != "" {
+		exportNetwork(path, *export)
+		return
+	}
 	part1(path)
 	part2(path)
 }
@@ -1,11 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
+	"github.com/iSkytran/2023adventofcode/utilities/poly"
 )
 
 func parseHistory(line string) []int {
@@ -13,86 +14,47 @@ func parseHistory(line string) []int {
 	return utilities.StringsToInts(tokens)
 }
 
-func interpolateNext(history []int) int {
-	// Find value of the next number in the history.
-	// If all zeros in list, next number is zero.
-	if allZeroes(history) {
-		return 0
-	}
-
-	// Must interpolate deltas first.
-	deltas := derivative(history)
-	nextDelta := interpolateNext(deltas)
-
-	// Next number is the last number plus the next delta.
-	return history[len(history)-1] + nextDelta
-}
-
-func interpolatePrev(history []int) int {
-	// Find value of the next number in the history.
-	// If all zeros in list, next number is zero.
-	if allZeroes(history) {
-		return 0
-	}
-
-	// Must interpolate deltas first.
-	deltas := derivative(history)
-	prevDelta := interpolatePrev(deltas)
-
-	// Next number is the first number minus the previous delta.
-	return history[0] - prevDelta
-}
-
-func derivative(history []int) []int {
-	// Compute differences between each element.
-	deltas := make([]int, 0)
-	for i := 1; i < len(history); i++ {
-		delta := history[i] - history[i-1]
-		deltas = append(deltas, delta)
-	}
-	return deltas
-}
-
-func allZeroes(history []int) bool {
-	for _, val := range history {
-		if val != 0 {
-			return false
-		}
-	}
-	return true
+func extrapolate(history []int, index int) utilities.Number {
+	// Fit a polynomial to the history and evaluate it at the index.
+	table, err := poly.NewDifferenceTable(history)
+	utilities.ErrorCheck(err)
+	return table.At(index)
 }
 
 func part1(path string) {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
-	sum := 0
+	sum := utilities.NewNumber(0)
 	for scanner.Scan() {
 		line := scanner.Text()
 		history := parseHistory(line)
-		sum += interpolateNext(history)
+		sum = sum.Add(extrapolate(history, len(history)))
 	}
 
-	fmt.Printf("Total: %d\n", sum)
+	fmt.Printf("Total: %v\n", sum)
 }
 
 func part2(path string) {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
-	sum := 0
+	sum := utilities.NewNumber(0)
 	for scanner.Scan() {
 		line := scanner.Text()
 		history := parseHistory(line)
-		sum += interpolatePrev(history)
+		sum = sum.Add(extrapolate(history, -1))
 	}
 
-	fmt.Printf("Total: %d\n", sum)
+	fmt.Printf("Total: %v\n", sum)
 }
 
 func main() {
+	utilities.BigIntFlag()
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
+	path := flag.Arg(0)
 	part1(path)
 	part2(path)
 }
@@ -1,29 +1,56 @@
 package main
 
 import (
+	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"slices"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
+	"github.com/iSkytran/2023adventofcode/utilities/geometry"
 )
 
+// Colors used when animating the loop.
+var palette = map[rune]string{
+	'S': utilities.AnsiGreen,
+	'.': utilities.AnsiDim,
+}
+
+// Box drawing characters used when rendering pipes.
+var boxDrawing = map[rune]rune{
+	'|': '│',
+	'-': '─',
+	'L': '└',
+	'J': '┘',
+	'7': '┐',
+	'F': '┌',
+}
+
+// Marks used when rendering tiles that aren't on the loop.
 const (
-	north = iota
-	south
-	west
-	east
+	insideMark  = 'I'
+	outsideMark = '·'
 )
 
-type coordinates struct {
-	row int
-	col int
+// Directions each pipe connects, in clockwise order.
+var pipeConnections = map[rune][]utilities.Direction{
+	'|': {utilities.North, utilities.South},
+	'-': {utilities.East, utilities.West},
+	'L': {utilities.North, utilities.East},
+	'J': {utilities.North, utilities.West},
+	'7': {utilities.South, utilities.West},
+	'F': {utilities.East, utilities.South},
 }
 
 type pipeMaze struct {
-	startCoord *coordinates
+	startCoord *utilities.Coordinates
+	startPipe  rune
 	diagram    [][]rune
-	loop       *utilities.Set[coordinates]
+	path       []utilities.Coordinates
+	loop       *utilities.Set[utilities.Coordinates]
 }
 
 func newMaze() *pipeMaze {
@@ -32,159 +59,140 @@ func newMaze() *pipeMaze {
 	return maze
 }
 
-func (maze *pipeMaze) adjacentToStart() ([]coordinates, *utilities.Set[int]) {
-	// Find direction to proceed in from start.
-	start := maze.startCoord
-	valid := map[int]coordinates{
-		north: {start.row - 1, start.col},
-		south: {start.row + 1, start.col},
-		west:  {start.row, start.col - 1},
-		east:  {start.row, start.col + 1},
+// Tile at the coordinates, if it's inside the diagram.
+func (maze *pipeMaze) at(coord utilities.Coordinates) (rune, bool) {
+	if coord.Row < 0 || coord.Row >= len(maze.diagram) {
+		return 0, false
 	}
-
-	if start.row == 0 {
-		// Cannot proceed North.
-		delete(valid, north)
-	}
-
-	if start.row == len(maze.diagram)-1 {
-		// Cannot proceed South.
-		delete(valid, south)
-	}
-
-	if start.col == 0 {
-		// Cannot proceed West.
-		delete(valid, west)
+	if coord.Col < 0 || coord.Col >= len(maze.diagram[coord.Row]) {
+		return 0, false
 	}
+	return maze.diagram[coord.Row][coord.Col], true
+}
 
-	if start.col == len(maze.diagram[0])-1 {
-		// Cannot proceed East.
-		delete(valid, west)
-	}
+// Check if the pipe at the coordinates has an opening in the direction.
+func (maze *pipeMaze) connects(coord utilities.Coordinates, direction utilities.Direction) bool {
+	pipe, _ := maze.at(coord)
+	return slices.Contains(pipeConnections[pipe], direction)
+}
 
-	adjacentCoords := make([]coordinates, 0)
-	adjacentDirections := utilities.NewSet[int]()
-	for direction, coords := range valid {
-		// Check if next pipe is connected.
-		pipe := string(maze.diagram[coords.row][coords.col])
+// Follow pipes leaving start in a direction until getting back to start. Returns
+// the tiles visited in order and the direction start was reentered from.
+func (maze *pipeMaze) walk(heading utilities.Direction) ([]utilities.Coordinates, utilities.Direction, error) {
+	path := []utilities.Coordinates{*maze.startCoord}
+	current := *maze.startCoord
+	for {
+		next := current.Add(heading.Delta())
+		pipe, ok := maze.at(next)
 		switch {
-		case direction == north && strings.Contains("|7F", pipe):
-			adjacentCoords = append(adjacentCoords, coords)
-			adjacentDirections.Add(direction)
-		case direction == south && strings.Contains("|JL", pipe):
-			adjacentCoords = append(adjacentCoords, coords)
-			adjacentDirections.Add(direction)
-		case direction == west && strings.Contains("-FL", pipe):
-			adjacentCoords = append(adjacentCoords, coords)
-			adjacentDirections.Add(direction)
-		case direction == east && strings.Contains("-7J", pipe):
-			adjacentCoords = append(adjacentCoords, coords)
-			adjacentDirections.Add(direction)
+		case !ok:
+			return nil, heading, fmt.Errorf("pipe at row %d col %d leads off the diagram", current.Row, current.Col)
+		case next == *maze.startCoord:
+			return path, heading.Opposite(), nil
+		case !maze.connects(next, heading.Opposite()):
+			return nil, heading, fmt.Errorf("dead end at row %d col %d, %q has no %s opening",
+				next.Row, next.Col, pipe, heading.Opposite())
 		}
-	}
 
-	// No valid pipe found.
-	return adjacentCoords, adjacentDirections
+		// Leave through the other opening.
+		back := heading.Opposite()
+		for _, direction := range pipeConnections[pipe] {
+			if direction != back {
+				heading = direction
+			}
+		}
+		current = next
+		path = append(path, current)
+	}
 }
 
-func (maze *pipeMaze) computeLoop() {
-	// Keep track of loop coordinates.
-	maze.loop = utilities.NewSet[coordinates]()
+func (maze *pipeMaze) computeLoop(visualizer utilities.Visualizer) error {
+	if maze.startCoord == nil {
+		return errors.New("no start tile in the diagram")
+	}
 
-	// Find the coordinates of the direction to go in.
-	adjacentCoords, _ := maze.adjacentToStart()
-	current := adjacentCoords[0]
+	// Every direction with a pipe opening back towards start may begin the loop.
+	candidates := make([]utilities.Direction, 0)
+	for _, direction := range utilities.Directions {
+		if maze.connects(maze.startCoord.Add(direction.Delta()), direction.Opposite()) {
+			candidates = append(candidates, direction)
+		}
+	}
+	if len(candidates) < 2 {
+		return fmt.Errorf("start at row %d col %d connects to %d pipes, a loop needs 2",
+			maze.startCoord.Row, maze.startCoord.Col, len(candidates))
+	}
 
-	leavingStart := true
-	for {
-		// Determine next coordinate.
-		currentPipe := maze.diagram[current.row][current.col]
-		valid := make(map[int]coordinates)
-		valid[north] = coordinates{current.row - 1, current.col}
-		valid[south] = coordinates{current.row + 1, current.col}
-		valid[west] = coordinates{current.row, current.col - 1}
-		valid[east] = coordinates{current.row, current.col + 1}
-
-		// Add current coordinates to visited.
-		maze.loop.Add(current)
-
-		// Remove invalid directions.
-		switch currentPipe {
-		case '|':
-			delete(valid, west)
-			delete(valid, east)
-		case '-':
-			delete(valid, north)
-			delete(valid, south)
-		case 'L':
-			delete(valid, west)
-			delete(valid, south)
-		case 'J':
-			delete(valid, east)
-			delete(valid, south)
-		case '7':
-			delete(valid, north)
-			delete(valid, east)
-		case 'F':
-			delete(valid, north)
-			delete(valid, west)
-		case 'S':
-			// Completed the loop.
-			return
+	// Walk from each candidate. A walk that gets back to start closes a loop, and
+	// each loop is found once from either end.
+	var loops []pipeLoop
+	var errs []error
+	for _, heading := range candidates {
+		path, entered, err := maze.walk(heading)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("heading %s: %w", heading, err))
+			continue
+		}
+		shape := []utilities.Direction{heading, entered}
+		slices.Sort(shape)
+		if !slices.ContainsFunc(loops, func(loop pipeLoop) bool { return slices.Equal(loop.shape, shape) }) {
+			loops = append(loops, pipeLoop{shape: shape, path: path})
 		}
+	}
 
-		// Remove locations visited.
-		for _, coord := range valid {
-			// If leaving start, don't visit start.
-			if leavingStart && coord == *maze.startCoord {
-				leavingStart = false
-				continue
-			}
+	switch {
+	case len(loops) == 0:
+		return fmt.Errorf("no loop passes through start: %w", errors.Join(errs...))
+	case len(loops) > 1:
+		return fmt.Errorf("start branches into %d different loops", len(loops))
+	}
 
-			if !maze.loop.Contains(coord) {
-				// Location not visited yet.
-				current = coord
-				break
-			}
+	// Start takes on the shape of the pipe joining the two ends of the loop.
+	for pipe, directions := range pipeConnections {
+		if slices.Equal(directions, loops[0].shape) {
+			maze.startPipe = pipe
 		}
 	}
+	maze.path = loops[0].path
+
+	// Grid view of the diagram for drawing frames.
+	grid := &utilities.Grid[rune]{Data: maze.diagram}
+	maze.loop = utilities.NewSet[utilities.Coordinates]()
+	for _, coord := range maze.path {
+		maze.loop.Add(coord)
+		visualizer.Frame(grid, maze.loop)
+	}
+	return nil
+}
+
+// Closed loop through start, along with the directions it leaves start in.
+type pipeLoop struct {
+	shape []utilities.Direction
+	path  []utilities.Coordinates
 }
 
 func (maze *pipeMaze) computeEnclosed() [][]rune {
 	// Make a copy of the diagram with just the loop.
 	diagram := make([][]rune, 0)
 	for rowNum, row := range maze.diagram {
-		for colNum := range row {
-			current := coordinates{rowNum, colNum}
-			if !maze.loop.Contains(current) {
-				row[colNum] = '.'
+		copied := make([]rune, len(row))
+		for colNum, pipe := range row {
+			copied[colNum] = '.'
+			if maze.loop.Contains(utilities.Coordinates{Row: rowNum, Col: colNum}) {
+				copied[colNum] = pipe
 			}
 		}
-		diagram = append(diagram, row)
+		diagram = append(diagram, copied)
 	}
 
 	// Replace start with pipe.
-	_, adjacentDirections := maze.adjacentToStart()
-	switch {
-	case adjacentDirections.Contains(north) && adjacentDirections.Contains(south):
-		diagram[maze.startCoord.row][maze.startCoord.col] = '|'
-	case adjacentDirections.Contains(west) && adjacentDirections.Contains(east):
-		diagram[maze.startCoord.row][maze.startCoord.col] = '-'
-	case adjacentDirections.Contains(north) && adjacentDirections.Contains(west):
-		diagram[maze.startCoord.row][maze.startCoord.col] = 'J'
-	case adjacentDirections.Contains(north) && adjacentDirections.Contains(east):
-		diagram[maze.startCoord.row][maze.startCoord.col] = 'L'
-	case adjacentDirections.Contains(west) && adjacentDirections.Contains(south):
-		diagram[maze.startCoord.row][maze.startCoord.col] = '7'
-	case adjacentDirections.Contains(east) && adjacentDirections.Contains(south):
-		diagram[maze.startCoord.row][maze.startCoord.col] = 'F'
-	}
+	diagram[maze.startCoord.Row][maze.startCoord.Col] = maze.startPipe
 
 	for rowNum := range diagram {
 		// Find number of times loop crossed.
 		// Even counts are outside the loop, odd counts are in the loop.
 		inLoop := false
-		for colNum := range diagram[0] {
+		for colNum := range diagram[rowNum] {
 			currentPipe := diagram[rowNum][colNum]
 
 			// Simple vertical case.
@@ -195,7 +203,7 @@ func (maze *pipeMaze) computeEnclosed() [][]rune {
 
 			// Lookahead vertical cases.
 			if currentPipe == 'F' {
-				for i := colNum; i < len(diagram[0]); i++ {
+				for i := colNum; i < len(diagram[rowNum]); i++ {
 					futurePipe := diagram[rowNum][i]
 					if futurePipe == 'J' {
 						// Zig-zag detected.
@@ -210,7 +218,7 @@ func (maze *pipeMaze) computeEnclosed() [][]rune {
 			}
 
 			if currentPipe == 'L' {
-				for i := colNum; i < len(diagram[0]); i++ {
+				for i := colNum; i < len(diagram[rowNum]); i++ {
 					futurePipe := diagram[rowNum][i]
 					if futurePipe == '7' {
 						// Zig-zag detected.
@@ -238,7 +246,7 @@ func (maze *pipeMaze) computeEnclosed() [][]rune {
 	return diagram
 }
 
-func parseMaze(path string) *pipeMaze {
+func parseMaze(path string, visualizer utilities.Visualizer) (*pipeMaze, error) {
 	scanner, file := utilities.OpenFile(path)
 	defer file.Close()
 
@@ -246,10 +254,13 @@ func parseMaze(path string) *pipeMaze {
 	rowNum := 0
 	for scanner.Scan() {
 		line := scanner.Text()
-		for colNum, pipeChar := range line {
+		for colNum, pipeChar := range []rune(line) {
 			if pipeChar == 'S' {
+				if maze.startCoord != nil {
+					return nil, fmt.Errorf("second start at row %d col %d", rowNum, colNum)
+				}
 				// Found start coordinates.
-				maze.startCoord = &coordinates{rowNum, colNum}
+				maze.startCoord = &utilities.Coordinates{Row: rowNum, Col: colNum}
 			}
 		}
 
@@ -258,22 +269,39 @@ func parseMaze(path string) *pipeMaze {
 	}
 
 	// Figure out loop coordinates.
-	maze.computeLoop()
-	return maze
+	if err := maze.computeLoop(visualizer); err != nil {
+		return nil, err
+	}
+	return maze, nil
 }
 
 func (maze *pipeMaze) stepsToEnd() int {
 	return maze.loop.Size() / 2
 }
 
-func part1(path string) {
-	maze := parseMaze(path)
+func part1(path string, visualizer utilities.Visualizer) {
+	maze, err := parseMaze(path, visualizer)
+	if err != nil {
+		fmt.Printf("No Solution: %v\n", err)
+		return
+	}
 	steps := maze.stepsToEnd()
 	fmt.Printf("Steps to Furthest: %d\n", steps)
 }
 
+// Count tiles inside the loop by treating it as a polygon through the tile
+// centers. Tiles on the loop are exactly the boundary lattice points.
+func (maze *pipeMaze) enclosedByArea() utilities.Number {
+	return geometry.NewPolygon(maze.path).InteriorPoints()
+}
+
 func part2(path string) {
-	maze := parseMaze(path)
+	maze, err := parseMaze(path, utilities.NoopVisualizer{})
+	if err != nil {
+		fmt.Printf("No Solution: %v\n", err)
+		return
+	}
+
 	diagram := maze.computeEnclosed()
 	count := 0
 	for _, row := range diagram {
@@ -286,9 +314,73 @@ func part2(path string) {
 	fmt.Printf("Number of Inner Tiles: %d\n", count)
 }
 
+// Draw the maze with box drawing characters. The loop is highlighted, inside
+// tiles are marked and outside tiles are dimmed when using ANSI colors. Tiles off
+// the loop are drawn the same whether or not they hold a pipe.
+func (maze *pipeMaze) render(out io.Writer, ansi bool) error {
+	writer := bufio.NewWriter(out)
+	enclosed := maze.computeEnclosed()
+	for rowNum, row := range enclosed {
+		for colNum, tile := range row {
+			var glyph rune
+			var color string
+			switch {
+			case rowNum == maze.startCoord.Row && colNum == maze.startCoord.Col:
+				glyph, color = boxDrawing[tile], utilities.AnsiBold+utilities.AnsiGreen
+			case maze.loop.Contains(utilities.Coordinates{Row: rowNum, Col: colNum}):
+				glyph, color = boxDrawing[tile], utilities.AnsiBold+utilities.AnsiYellow
+			case tile == 'I':
+				glyph, color = insideMark, utilities.AnsiCyan
+			default:
+				glyph, color = outsideMark, utilities.AnsiDim
+			}
+
+			if ansi {
+				writer.WriteString(color + string(glyph) + utilities.AnsiReset)
+			} else {
+				writer.WriteRune(glyph)
+			}
+		}
+		writer.WriteRune('\n')
+	}
+	return writer.Flush()
+}
+
+func renderMaze(path string, ansi bool, out io.Writer) error {
+	maze, err := parseMaze(path, utilities.NoopVisualizer{})
+	if err != nil {
+		return err
+	}
+	return maze.render(out, ansi)
+}
+
 func main() {
+	opts := utilities.VisualizerFlags()
+	render := flag.Bool("render", false, "print the maze with box drawing characters and colors")
+	renderFile := flag.String("render-file", "", "write the rendered maze without colors to this file")
+	flag.Parse()
+
+	// Animate the loop tracing if requested.
+	visualizer := opts.NewVisualizer(palette)
+	defer visualizer.Close()
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
+	path := flag.Arg(0)
+	part1(path, visualizer)
 	part2(path)
+
+	if *render {
+		if err := renderMaze(path, true, os.Stdout); err != nil {
+			fmt.Printf("No Rendering: %v\n", err)
+		}
+	}
+
+	if *renderFile != "" {
+		file, err := os.Create(*renderFile)
+		utilities.ErrorCheck(err)
+		defer file.Close()
+		if err := renderMaze(path, false, file); err != nil {
+			fmt.Printf("No Rendering: %v\n", err)
+		}
+	}
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iSkytran/2023adventofcode/utilities"
+)
+
+var enclosedTests = []struct {
+	name  string
+	maze  []string
+	inner int
+}{
+	{
+		name: "simple loop",
+		maze: []string{
+			".....",
+			".S-7.",
+			".|.|.",
+			".L-J.",
+			".....",
+		},
+		inner: 1,
+	},
+	{
+		name: "complex loop",
+		maze: []string{
+			"..F7.",
+			".FJ|.",
+			"SJ.L7",
+			"|F--J",
+			"LJ...",
+		},
+		inner: 1,
+	},
+	{
+		name: "squeezing between pipes",
+		maze: []string{
+			"...........",
+			".S-------7.",
+			".|F-----7|.",
+			".||.....||.",
+			".||.....||.",
+			".|L-7.F-J|.",
+			".|..|.|..|.",
+			".L--J.L--J.",
+			"...........",
+		},
+		inner: 4,
+	},
+	{
+		name: "larger example",
+		maze: []string{
+			".F----7F7F7F7F-7....",
+			".|F--7||||||||FJ....",
+			".||.FJ||||||||L7....",
+			"FJL7L7LJLJ||LJ.L-7..",
+			"L--J.L7...LJS7F-7L7.",
+			"....F-J..F7FJ|L7L7L7",
+			"....L7.F7||L7|.L7L7|",
+			".....|FJLJ|FJ|F7|.LJ",
+			"....FJL-7.||.||||...",
+			"....L---J.LJ.LJLJ...",
+		},
+		inner: 8,
+	},
+	{
+		name: "junk pipes",
+		maze: []string{
+			"FF7FSF7F7F7F7F7F---7",
+			"L|LJ||||||||||||F--J",
+			"FL-7LJLJ||||||LJL-77",
+			"F--JF--7||LJLJ7F7FJ-",
+			"L---JF-JLJ.||-FJLJJ7",
+			"|F|F-JF---7F7-L7L|7|",
+			"|FFJF7L7F-JF7|JL---7",
+			"7-L-JL7||F7|L7F-7F7|",
+			"L.L7LFJ|||||FJL7||LJ",
+			"L7JLJL-JLJLJL--JLJ.L",
+		},
+		inner: 10,
+	},
+	{
+		name: "start on the east border",
+		maze: []string{
+			"F---7",
+			"|...|",
+			"|.F-S",
+			"|.|..",
+			"L-J..",
+		},
+		inner: 5,
+	},
+}
+
+func writeMaze(t *testing.T, lines []string) string {
+	path := filepath.Join(t.TempDir(), "maze.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEnclosedMethodsAgree(t *testing.T) {
+	for _, test := range enclosedTests {
+		maze, err := parseMaze(writeMaze(t, test.maze), utilities.NoopVisualizer{})
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		scanned := 0
+		for _, row := range maze.computeEnclosed() {
+			scanned += strings.Count(string(row), "I")
+		}
+		byArea, ok := maze.enclosedByArea().Int()
+		if !ok {
+			t.Errorf("%s: enclosed area does not fit in an int", test.name)
+			continue
+		}
+
+		if scanned != byArea {
+			t.Errorf("%s: row scan found %d inner tiles, Pick's theorem found %d", test.name, scanned, byArea)
+		}
+		if scanned != test.inner {
+			t.Errorf("%s: found %d inner tiles, want %d", test.name, scanned, test.inner)
+		}
+	}
+}
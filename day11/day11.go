@@ -1,9 +1,8 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math"
-	"os"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
@@ -59,14 +58,13 @@ func cosmicExpansion(grid *utilities.Grid[rune], expansionFactor int) []utilitie
 	return galaxies
 }
 
-func manhattanDistance(coords []utilities.Coordinates) int {
-	sum := 0
+func manhattanDistance(coords []utilities.Coordinates) utilities.Number {
+	sum := utilities.NewNumber(0)
 	for i := 0; i < len(coords); i++ {
 		iCoords := coords[i]
 		for j := i; j < len(coords); j++ {
 			jCoords := coords[j]
-			sum += int(math.Abs(float64(iCoords.Row - jCoords.Row)))
-			sum += int(math.Abs(float64(iCoords.Col - jCoords.Col)))
+			sum = sum.Add(utilities.NewNumber(iCoords.Manhattan(jCoords)))
 		}
 	}
 	return sum
@@ -76,19 +74,22 @@ func part1(path string) {
 	grid := utilities.GridFromFile(path)
 	coords := cosmicExpansion(grid, 2)
 	sum := manhattanDistance(coords)
-	fmt.Printf("Total: %d\n", sum)
+	fmt.Printf("Total: %v\n", sum)
 }
 
 func part2(path string) {
 	grid := utilities.GridFromFile(path)
 	coords := cosmicExpansion(grid, 1000000)
 	sum := manhattanDistance(coords)
-	fmt.Printf("Total: %d\n", sum)
+	fmt.Printf("Total: %v\n", sum)
 }
 
 func main() {
+	utilities.BigIntFlag()
+	flag.Parse()
+
 	// Input file.
-	path := os.Args[1]
+	path := flag.Arg(0)
 	part1(path)
 	part2(path)
 }
@@ -1,28 +1,27 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"os"
 	"slices"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
 
-const (
-	north = iota
-	west
-	south
-	east
-)
+// Colors used when animating the tilts.
+var palette = map[rune]string{
+	'O': utilities.AnsiCyan,
+	'#': utilities.AnsiDim,
+}
 
-func cycle(grid *utilities.Grid[rune], numCycles int) {
+func cycle(grid *utilities.Grid[rune], numCycles int, visualizer utilities.Visualizer) {
 	seen := map[string]int{}
 	foundRepeat := false
 	for i := 0; i < numCycles; i++ {
-		directionalShift(grid, north)
-		directionalShift(grid, west)
-		directionalShift(grid, south)
-		directionalShift(grid, east)
+		for _, direction := range []utilities.Direction{utilities.North, utilities.West, utilities.South, utilities.East} {
+			directionalShift(grid, direction)
+			visualizer.Frame(grid, nil)
+		}
 
 		if !foundRepeat {
 			serialization := grid.Serialize()
@@ -39,32 +38,32 @@ func cycle(grid *utilities.Grid[rune], numCycles int) {
 	}
 }
 
-func directionalShift(grid *utilities.Grid[rune], direction int) {
+func directionalShift(grid *utilities.Grid[rune], direction utilities.Direction) {
 	var size int
 	switch direction {
-	case north, south:
+	case utilities.North, utilities.South:
 		size = grid.ColSize()
-	case east, west:
+	case utilities.East, utilities.West:
 		size = grid.RowSize()
 	}
 
 	for i := 0; i < size; i++ {
 		switch direction {
-		case north:
+		case utilities.North:
 			slice, _ := grid.GetColumn(i)
 			slice = shift(slice)
 			grid.SetColumn(i, slice)
-		case west:
+		case utilities.West:
 			slice, _ := grid.GetRow(i)
 			slice = shift(slice)
 			grid.SetRow(i, slice)
-		case south:
+		case utilities.South:
 			slice, _ := grid.GetColumn(i)
 			slices.Reverse[[]rune](slice)
 			slice = shift(slice)
 			slices.Reverse[[]rune](slice)
 			grid.SetColumn(i, slice)
-		case east:
+		case utilities.East:
 			slice, _ := grid.GetRow(i)
 			slices.Reverse[[]rune](slice)
 			slice = shift(slice)
@@ -113,21 +112,28 @@ func calcLoad(grid *utilities.Grid[rune]) int {
 
 func part1(path string) {
 	grid := utilities.GridFromFile(path)
-	directionalShift(grid, north)
+	directionalShift(grid, utilities.North)
 	load := calcLoad(grid)
 	fmt.Printf("Total Load: %d\n", load)
 }
 
-func part2(path string) {
+func part2(path string, visualizer utilities.Visualizer) {
 	grid := utilities.GridFromFile(path)
-	cycle(grid, 1e9)
+	cycle(grid, 1e9, visualizer)
 	load := calcLoad(grid)
 	fmt.Printf("Total Load: %d\n", load)
 }
 
 func main() {
+	opts := utilities.VisualizerFlags()
+	flag.Parse()
+
+	// Animate the spin cycles if requested.
+	visualizer := opts.NewVisualizer(palette)
+	defer visualizer.Close()
+
 	// Input file.
-	path := os.Args[1]
+	path := flag.Arg(0)
 	part1(path)
-	part2(path)
+	part2(path, visualizer)
 }
@@ -1,19 +1,37 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
-	"os"
 
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
 
-var (
-	up    = utilities.Coordinates{Row: -1, Col: 0}
-	down  = utilities.Coordinates{Row: 1, Col: 0}
-	left  = utilities.Coordinates{Row: 0, Col: -1}
-	right = utilities.Coordinates{Row: 0, Col: 1}
-)
+// Colors used when animating the beams.
+var palette = map[rune]string{
+	'/':  utilities.AnsiCyan,
+	'\\': utilities.AnsiCyan,
+	'|':  utilities.AnsiMagenta,
+	'-':  utilities.AnsiMagenta,
+}
+
+// State shared while tracing beams through the grid.
+type beamTracer struct {
+	grid       *utilities.Grid[rune]
+	visited    *utilities.Set[utilities.Vector]
+	energized  *utilities.Set[utilities.Coordinates]
+	visualizer utilities.Visualizer
+}
+
+func newBeamTracer(grid *utilities.Grid[rune], visualizer utilities.Visualizer) *beamTracer {
+	tracer := new(beamTracer)
+	tracer.grid = grid
+	tracer.visited = utilities.NewSet[utilities.Vector]()
+	tracer.energized = utilities.NewSet[utilities.Coordinates]()
+	tracer.visualizer = visualizer
+	return tracer
+}
 
 func optimalCoverage(grid *utilities.Grid[rune]) int {
 	max := 0.0
@@ -21,144 +39,139 @@ func optimalCoverage(grid *utilities.Grid[rune]) int {
 	// Traverse left to right.
 	for i := 0; i < grid.ColSize(); i++ {
 		// Check top.
-		start := utilities.Vector{Origin: utilities.Coordinates{Row: 0, Col: i}, Direction: down}
-		energized := float64(coverage(grid, start))
+		start := utilities.Vector{Origin: utilities.Coordinates{Row: 0, Col: i}, Direction: utilities.South}
+		energized := float64(coverage(grid, start, utilities.NoopVisualizer{}))
 		max = math.Max(max, energized)
 
 		// Check bottom.
-		start = utilities.Vector{Origin: utilities.Coordinates{Row: grid.RowSize() - 1, Col: i}, Direction: up}
-		energized = float64(coverage(grid, start))
+		start = utilities.Vector{Origin: utilities.Coordinates{Row: grid.RowSize() - 1, Col: i}, Direction: utilities.North}
+		energized = float64(coverage(grid, start, utilities.NoopVisualizer{}))
 		max = math.Max(max, energized)
 	}
 
 	// Traverse top to bottom.
 	for i := 0; i < grid.RowSize(); i++ {
 		// Check left
-		start := utilities.Vector{Origin: utilities.Coordinates{Row: i, Col: 0}, Direction: right}
-		energized := float64(coverage(grid, start))
+		start := utilities.Vector{Origin: utilities.Coordinates{Row: i, Col: 0}, Direction: utilities.East}
+		energized := float64(coverage(grid, start, utilities.NoopVisualizer{}))
 		max = math.Max(max, energized)
 
 		// Check right.
-		start = utilities.Vector{Origin: utilities.Coordinates{Row: i, Col: grid.ColSize() - 1}, Direction: left}
-		energized = float64(coverage(grid, start))
+		start = utilities.Vector{Origin: utilities.Coordinates{Row: i, Col: grid.ColSize() - 1}, Direction: utilities.West}
+		energized = float64(coverage(grid, start, utilities.NoopVisualizer{}))
 		max = math.Max(max, energized)
 	}
 
 	return int(max)
 }
 
-func coverage(grid *utilities.Grid[rune], start utilities.Vector) int {
-	visited := utilities.NewSet[utilities.Vector]()
-	visitCoordinate(grid, visited, start)
-
-	// Extract locations from vectors.
-	energized := utilities.NewSet[utilities.Coordinates]()
-	for _, vector := range visited.ToSlice() {
-		energized.Add(vector.Origin)
-	}
-
-	return energized.Size()
+func coverage(grid *utilities.Grid[rune], start utilities.Vector, visualizer utilities.Visualizer) int {
+	tracer := newBeamTracer(grid, visualizer)
+	visitCoordinate(tracer, start)
+	return tracer.energized.Size()
 }
 
-func visitCoordinate(grid *utilities.Grid[rune], set *utilities.Set[utilities.Vector], current utilities.Vector) {
+func visitCoordinate(tracer *beamTracer, current utilities.Vector) {
 	// Check coordinates are in bounds.
-	if !grid.CoordInGrid(current.Origin) {
+	if !tracer.grid.CoordInGrid(current.Origin) {
 		return
 	}
 
 	// Visit current coordinate.
-	if set.Contains(current) {
+	if tracer.visited.Contains(current) {
 		// Beam exists, ignore.
 		return
 	}
-	set.Add(current)
+	tracer.visited.Add(current)
+	tracer.energized.Add(current.Origin)
+	tracer.visualizer.Frame(tracer.grid, tracer.energized)
 
 	// Compute next coordinate(s).
-	currentRune, _ := grid.GetByCoord(current.Origin)
+	currentRune, _ := tracer.grid.GetByCoord(current.Origin)
 	switch currentRune {
 	case '.':
 		// Continue in current direction.
-		current.Origin = current.Origin.Add(current.Direction)
-		visitCoordinate(grid, set, current)
+		current.Origin = current.Origin.Add(current.Direction.Delta())
+		visitCoordinate(tracer, current)
 	case '/':
 		switch current.Direction {
-		case up:
+		case utilities.North:
 			// Bounce right.
-			current.Origin = current.Origin.Add(right)
-			current.Direction = right
-			visitCoordinate(grid, set, current)
-		case down:
+			current.Origin = current.Origin.Add(utilities.East.Delta())
+			current.Direction = utilities.East
+			visitCoordinate(tracer, current)
+		case utilities.South:
 			// Bounce left.
-			current.Origin = current.Origin.Add(left)
-			current.Direction = left
-			visitCoordinate(grid, set, current)
-		case left:
+			current.Origin = current.Origin.Add(utilities.West.Delta())
+			current.Direction = utilities.West
+			visitCoordinate(tracer, current)
+		case utilities.West:
 			// Bounce down.
-			current.Origin = current.Origin.Add(down)
-			current.Direction = down
-			visitCoordinate(grid, set, current)
-		case right:
+			current.Origin = current.Origin.Add(utilities.South.Delta())
+			current.Direction = utilities.South
+			visitCoordinate(tracer, current)
+		case utilities.East:
 			// Bounce up.
-			current.Origin = current.Origin.Add(up)
-			current.Direction = up
-			visitCoordinate(grid, set, current)
+			current.Origin = current.Origin.Add(utilities.North.Delta())
+			current.Direction = utilities.North
+			visitCoordinate(tracer, current)
 		}
 	case '\\':
 		switch current.Direction {
-		case up:
+		case utilities.North:
 			// Bounce left.
-			current.Origin = current.Origin.Add(left)
-			current.Direction = left
-			visitCoordinate(grid, set, current)
-		case down:
+			current.Origin = current.Origin.Add(utilities.West.Delta())
+			current.Direction = utilities.West
+			visitCoordinate(tracer, current)
+		case utilities.South:
 			// Bounce right.
-			current.Origin = current.Origin.Add(right)
-			current.Direction = right
-			visitCoordinate(grid, set, current)
-		case left:
+			current.Origin = current.Origin.Add(utilities.East.Delta())
+			current.Direction = utilities.East
+			visitCoordinate(tracer, current)
+		case utilities.West:
 			// Bounce up.
-			current.Origin = current.Origin.Add(up)
-			current.Direction = up
-			visitCoordinate(grid, set, current)
-		case right:
+			current.Origin = current.Origin.Add(utilities.North.Delta())
+			current.Direction = utilities.North
+			visitCoordinate(tracer, current)
+		case utilities.East:
 			// Bounce down.
-			current.Origin = current.Origin.Add(down)
-			current.Direction = down
-			visitCoordinate(grid, set, current)
+			current.Origin = current.Origin.Add(utilities.South.Delta())
+			current.Direction = utilities.South
+			visitCoordinate(tracer, current)
 		}
 	case '|':
 		switch current.Direction {
-		case up, down:
+		case utilities.North, utilities.South:
 			// Continue in current direction.
-			current.Origin = current.Origin.Add(current.Direction)
-			visitCoordinate(grid, set, current)
-		case left, right:
+			current.Origin = current.Origin.Add(current.Direction.Delta())
+			visitCoordinate(tracer, current)
+		case utilities.West, utilities.East:
 			// Split into up and down beams.
-			next := utilities.Vector{Origin: current.Origin.Add(up), Direction: up}
-			visitCoordinate(grid, set, next)
-			next = utilities.Vector{Origin: current.Origin.Add(down), Direction: down}
-			visitCoordinate(grid, set, next)
+			next := utilities.Vector{Origin: current.Origin.Add(utilities.North.Delta()), Direction: utilities.North}
+			visitCoordinate(tracer, next)
+			next = utilities.Vector{Origin: current.Origin.Add(utilities.South.Delta()), Direction: utilities.South}
+			visitCoordinate(tracer, next)
 		}
 	case '-':
 		switch current.Direction {
-		case up, down:
+		case utilities.North, utilities.South:
 			// Split into left and right beams.
-			next := utilities.Vector{Origin: current.Origin.Add(left), Direction: left}
-			visitCoordinate(grid, set, next)
-			next = utilities.Vector{Origin: current.Origin.Add(right), Direction: right}
-			visitCoordinate(grid, set, next)
-		case left, right:
+			next := utilities.Vector{Origin: current.Origin.Add(utilities.West.Delta()), Direction: utilities.West}
+			visitCoordinate(tracer, next)
+			next = utilities.Vector{Origin: current.Origin.Add(utilities.East.Delta()), Direction: utilities.East}
+			visitCoordinate(tracer, next)
+		case utilities.West, utilities.East:
 			// Continue in current direction.
-			current.Origin = current.Origin.Add(current.Direction)
-			visitCoordinate(grid, set, current)
+			current.Origin = current.Origin.Add(current.Direction.Delta())
+			visitCoordinate(tracer, current)
 		}
 	}
 }
 
-func part1(path string) {
+func part1(path string, visualizer utilities.Visualizer) {
 	grid := utilities.GridFromFile(path)
-	start := utilities.Vector{Origin: utilities.Coordinates{Row: 0, Col: 0}, Direction: right}
-	energized := coverage(grid, start)
+	start := utilities.Vector{Origin: utilities.Coordinates{Row: 0, Col: 0}, Direction: utilities.East}
+	energized := coverage(grid, start, visualizer)
 	fmt.Printf("Energized: %d\n", energized)
 }
 
@@ -169,8 +182,15 @@ func part2(path string) {
 }
 
 func main() {
+	opts := utilities.VisualizerFlags()
+	flag.Parse()
+
+	// Animate the beam from the top left if requested.
+	visualizer := opts.NewVisualizer(palette)
+	defer visualizer.Close()
+
 	// Input file.
-	path := os.Args[1]
-	part1(path)
+	path := flag.Arg(0)
+	part1(path, visualizer)
 	part2(path)
 }
@@ -8,19 +8,11 @@ import (
 	"github.com/iSkytran/2023adventofcode/utilities"
 )
 
-// List of directions that can be added to coordinates.
-var directions = []utilities.Coordinates{
-	{Row: -1, Col: 0},
-	{Row: 1, Col: 0},
-	{Row: 0, Col: -1},
-	{Row: 0, Col: 1},
-}
-
 // A state while exploring the grid taking into account location, direction, and
 // number of straight steps.
 type pathState struct {
 	loc       utilities.Coordinates
-	direction utilities.Coordinates
+	direction utilities.Direction
 	steps     int
 }
 
@@ -45,11 +37,14 @@ func dijkstra(grid *utilities.Grid[int], start utilities.Coordinates, end utilit
 	pq := new(utilities.MinPriorityQueue[pathState])
 	heap.Init(pq)
 
-	// Add start to queue.
-	startState := pathState{loc: start, direction: utilities.Coordinates{Row: 0, Col: 0}, steps: 0}
-	startElement := utilities.PriorityElement[pathState]{Value: startState, Priority: 0}
-	heap.Push(pq, startElement)
-	distance[startState] = 0
+	// Add start to queue facing every way. A step count of -1 makes the first move
+	// count as the start of a straight line.
+	for _, direction := range utilities.Directions {
+		startState := pathState{loc: start, direction: direction, steps: -1}
+		startElement := utilities.PriorityElement[pathState]{Value: startState, Priority: 0}
+		heap.Push(pq, startElement)
+		distance[startState] = 0
+	}
 
 	for pq.Len() != 0 {
 		// Visit next closest coordinate.
@@ -60,14 +55,13 @@ func dijkstra(grid *utilities.Grid[int], start utilities.Coordinates, end utilit
 			return nextElement.Priority
 		}
 
-		for _, direction := range directions {
+		for _, direction := range utilities.Directions {
 			// Disallow backtracking.
-			zeroCoord := utilities.Coordinates{Row: 0, Col: 0}
-			if direction.Add(u.direction) == zeroCoord {
+			if direction == u.direction.Opposite() {
 				continue
 			}
 
-			vOrigin := u.loc.Add(direction)
+			vOrigin := u.loc.Add(direction.Delta())
 			v := pathState{loc: vOrigin, direction: direction, steps: u.steps + 1}
 
 			if direction == u.direction {
@@ -76,7 +70,7 @@ func dijkstra(grid *utilities.Grid[int], start utilities.Coordinates, end utilit
 					continue
 				}
 			} else {
-				if v.steps < minLine && u.direction != zeroCoord {
+				if v.steps < minLine && u.steps >= 0 {
 					// Disallow turning before minLine. If not starting.
 					continue
 				}
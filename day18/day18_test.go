@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var example = []string{
+	"R 6 (#70c710)",
+	"D 5 (#0dc571)",
+	"L 2 (#5713f0)",
+	"D 2 (#d2c081)",
+	"R 2 (#59c680)",
+	"D 2 (#411b91)",
+	"L 5 (#8ceee2)",
+	"U 2 (#caa173)",
+	"L 1 (#1b58a2)",
+	"U 2 (#caa171)",
+	"R 2 (#7807d2)",
+	"U 3 (#a77fa3)",
+	"L 2 (#015232)",
+	"U 2 (#7a21e3)",
+}
+
+func TestLagoonSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(example, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := lagoonSize(parseInput(path)); got.String() != "62" {
+		t.Errorf("lagoonSize with plain instructions = %v, want 62", got)
+	}
+	if got := lagoonSize(parseInputHex(path)); got.String() != "952408144115" {
+		t.Errorf("lagoonSize with hex instructions = %v, want 952408144115", got)
+	}
+}
@@ -0,0 +1,123 @@
+package ahocorasick
+
+// ******************************************* //
+// Aho-Corasick multi-pattern string matching. //
+// ******************************************* //
+
+// A dictionary pattern found in some text. Start and End are byte offsets, with
+// End being exclusive.
+type Match struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+// Automaton built from a dictionary of patterns.
+type Matcher struct {
+	patterns []string
+	nodes    []*trieNode
+}
+
+type trieNode struct {
+	next   map[byte]int
+	fail   int
+	output []int
+}
+
+func newTrieNode() *trieNode {
+	node := new(trieNode)
+	node.next = make(map[byte]int)
+	node.output = make([]int, 0)
+	return node
+}
+
+func New(patterns []string) *Matcher {
+	matcher := new(Matcher)
+	matcher.patterns = append([]string{}, patterns...)
+	matcher.nodes = []*trieNode{newTrieNode()}
+
+	// Build the trie of all patterns.
+	for idx, pattern := range matcher.patterns {
+		if pattern == "" {
+			continue
+		}
+
+		current := 0
+		for i := 0; i < len(pattern); i++ {
+			next, ok := matcher.nodes[current].next[pattern[i]]
+			if !ok {
+				next = len(matcher.nodes)
+				matcher.nodes = append(matcher.nodes, newTrieNode())
+				matcher.nodes[current].next[pattern[i]] = next
+			}
+			current = next
+		}
+		matcher.nodes[current].output = append(matcher.nodes[current].output, idx)
+	}
+
+	// Breadth first to set failure links to the longest proper suffix in the trie.
+	queue := make([]int, 0)
+	for _, child := range matcher.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for char, child := range matcher.nodes[current].next {
+			queue = append(queue, child)
+
+			fail := matcher.nodes[current].fail
+			for fail != 0 && !matcher.hasEdge(fail, char) {
+				fail = matcher.nodes[fail].fail
+			}
+			if next, ok := matcher.nodes[fail].next[char]; ok && next != child {
+				fail = next
+			}
+			matcher.nodes[child].fail = fail
+
+			// Patterns ending at the suffix also end here.
+			inherited := matcher.nodes[fail].output
+			matcher.nodes[child].output = append(matcher.nodes[child].output, inherited...)
+		}
+	}
+
+	return matcher
+}
+
+func (matcher *Matcher) hasEdge(node int, char byte) bool {
+	_, ok := matcher.nodes[node].next[char]
+	return ok
+}
+
+func (matcher *Matcher) Patterns() []string {
+	return append([]string{}, matcher.patterns...)
+}
+
+// Visit every match in a single pass, including overlapping ones, ordered by
+// where they end.
+func (matcher *Matcher) Scan(text string, visit func(Match)) {
+	current := 0
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+		for current != 0 && !matcher.hasEdge(current, char) {
+			current = matcher.nodes[current].fail
+		}
+		if next, ok := matcher.nodes[current].next[char]; ok {
+			current = next
+		}
+
+		for _, idx := range matcher.nodes[current].output {
+			end := i + 1
+			visit(Match{Pattern: idx, Start: end - len(matcher.patterns[idx]), End: end})
+		}
+	}
+}
+
+func (matcher *Matcher) FindAll(text string) []Match {
+	matches := make([]Match, 0)
+	matcher.Scan(text, func(match Match) {
+		matches = append(matches, match)
+	})
+	return matches
+}
@@ -0,0 +1,72 @@
+package utilities
+
+import "fmt"
+
+// ******************************************** //
+// Direction enumeration and related functions. //
+// ******************************************** //
+type Direction int
+
+// Directions in clockwise order.
+const (
+	North Direction = iota
+	East
+	South
+	West
+)
+
+// All directions in clockwise order starting from North.
+var Directions = []Direction{North, East, South, West}
+
+var directionDeltas = map[Direction]Coordinates{
+	North: {Row: -1, Col: 0},
+	East:  {Row: 0, Col: 1},
+	South: {Row: 1, Col: 0},
+	West:  {Row: 0, Col: -1},
+}
+
+var directionNames = map[Direction]string{
+	North: "North",
+	East:  "East",
+	South: "South",
+	West:  "West",
+}
+
+// Accepted spellings of each direction.
+var directionAliases = map[string]Direction{
+	"U": North, "N": North, "^": North,
+	"R": East, "E": East, ">": East,
+	"D": South, "S": South, "v": South,
+	"L": West, "W": West, "<": West,
+}
+
+func ParseDirection(str string) (Direction, error) {
+	if direction, ok := directionAliases[str]; ok {
+		return direction, nil
+	}
+	return North, fmt.Errorf("unknown direction %q", str)
+}
+
+// Coordinates to add to move one step in the direction.
+func (d Direction) Delta() Coordinates {
+	return directionDeltas[d]
+}
+
+func (d Direction) TurnLeft() Direction {
+	return (d + 3) % 4
+}
+
+func (d Direction) TurnRight() Direction {
+	return (d + 1) % 4
+}
+
+func (d Direction) Opposite() Direction {
+	return (d + 2) % 4
+}
+
+func (d Direction) String() string {
+	if name, ok := directionNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
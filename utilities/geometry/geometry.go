@@ -0,0 +1,88 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/iSkytran/2023adventofcode/utilities"
+	"github.com/iSkytran/2023adventofcode/utilities/numth"
+)
+
+// **************************************** //
+// Polygon structure and related functions. //
+// **************************************** //
+
+// Simple polygon with vertices on lattice points, listed in order around the
+// boundary. The last vertex joins back up with the first. Sums fall back to
+// math/big rather than overflowing.
+type Polygon struct {
+	vertices []utilities.Coordinates
+}
+
+func NewPolygon(vertices []utilities.Coordinates) *Polygon {
+	p := new(Polygon)
+	p.vertices = append([]utilities.Coordinates{}, vertices...)
+
+	// Closing the loop explicitly is allowed but not needed.
+	if len(p.vertices) > 1 && p.vertices[0] == p.vertices[len(p.vertices)-1] {
+		p.vertices = p.vertices[:len(p.vertices)-1]
+	}
+	return p
+}
+
+func (p *Polygon) Vertices() []utilities.Coordinates {
+	return append([]utilities.Coordinates{}, p.vertices...)
+}
+
+// Determinant of the 2 by 2 matrix with the coordinates as rows.
+func Det(a utilities.Coordinates, b utilities.Coordinates) utilities.Number {
+	diagonal := utilities.NewNumber(a.Row).Multiply(utilities.NewNumber(b.Col))
+	antiDiagonal := utilities.NewNumber(a.Col).Multiply(utilities.NewNumber(b.Row))
+	return diagonal.Subtract(antiDiagonal)
+}
+
+// Visit each edge of the polygon, including the one closing it.
+func (p *Polygon) edges(visit func(from utilities.Coordinates, to utilities.Coordinates)) {
+	for i, from := range p.vertices {
+		visit(from, p.vertices[(i+1)%len(p.vertices)])
+	}
+}
+
+// Twice the area using the shoelace formula, which is always a whole number.
+func (p *Polygon) TwiceArea() utilities.Number {
+	area := utilities.NewNumber(0)
+	p.edges(func(from, to utilities.Coordinates) {
+		area = area.Add(Det(from, to))
+	})
+	return area.Abs()
+}
+
+// Area rounded down to a whole number.
+func (p *Polygon) Area() utilities.Number {
+	return p.TwiceArea().Divide(utilities.NewNumber(2))
+}
+
+// Length of the boundary.
+func (p *Polygon) Perimeter() float64 {
+	perimeter := 0.0
+	p.edges(func(from, to utilities.Coordinates) {
+		delta := to.Subtract(from)
+		perimeter += math.Hypot(float64(delta.Row), float64(delta.Col))
+	})
+	return perimeter
+}
+
+// Lattice points on the boundary, including the vertices.
+func (p *Polygon) BoundaryPoints() utilities.Number {
+	points := utilities.NewNumber(0)
+	p.edges(func(from, to utilities.Coordinates) {
+		delta := to.Subtract(from)
+		points = points.Add(utilities.NewNumber(numth.GCD(delta.Row, delta.Col)))
+	})
+	return points
+}
+
+// Lattice points strictly inside the boundary using Pick's theorem, A = I + B/2 - 1.
+func (p *Polygon) InteriorPoints() utilities.Number {
+	twice := p.TwiceArea().Subtract(p.BoundaryPoints()).Add(utilities.NewNumber(2))
+	return twice.Divide(utilities.NewNumber(2))
+}
@@ -0,0 +1,94 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/iSkytran/2023adventofcode/utilities"
+)
+
+func coords(points ...[2]int) []utilities.Coordinates {
+	result := make([]utilities.Coordinates, 0)
+	for _, point := range points {
+		result = append(result, utilities.Coordinates{Row: point[0], Col: point[1]})
+	}
+	return result
+}
+
+func TestPolygon(t *testing.T) {
+	tests := []struct {
+		name      string
+		vertices  []utilities.Coordinates
+		area      int
+		twiceArea int
+		perimeter float64
+		boundary  int
+		interior  int
+	}{
+		{
+			name:      "unit square",
+			vertices:  coords([2]int{0, 0}, [2]int{0, 1}, [2]int{1, 1}, [2]int{1, 0}),
+			area:      1,
+			twiceArea: 2,
+			perimeter: 4,
+			boundary:  4,
+			interior:  0,
+		},
+		{
+			name:      "square listed counterclockwise and closed explicitly",
+			vertices:  coords([2]int{0, 0}, [2]int{4, 0}, [2]int{4, 4}, [2]int{0, 4}, [2]int{0, 0}),
+			area:      16,
+			twiceArea: 32,
+			perimeter: 16,
+			boundary:  16,
+			interior:  9,
+		},
+		{
+			name:      "right triangle",
+			vertices:  coords([2]int{0, 0}, [2]int{0, 4}, [2]int{3, 0}),
+			area:      6,
+			twiceArea: 12,
+			perimeter: 12,
+			boundary:  8,
+			interior:  3,
+		},
+		{
+			name:      "half unit triangle",
+			vertices:  coords([2]int{0, 0}, [2]int{0, 1}, [2]int{1, 0}),
+			area:      0,
+			twiceArea: 1,
+			perimeter: 2 + math.Sqrt2,
+			boundary:  3,
+			interior:  0,
+		},
+		{
+			name: "L shape",
+			vertices: coords([2]int{0, 0}, [2]int{0, 2}, [2]int{2, 2}, [2]int{2, 4},
+				[2]int{4, 4}, [2]int{4, 0}),
+			area:      12,
+			twiceArea: 24,
+			perimeter: 16,
+			boundary:  16,
+			interior:  5,
+		},
+	}
+
+	for _, test := range tests {
+		polygon := NewPolygon(test.vertices)
+		if got := polygon.Area(); got.Cmp(utilities.NewNumber(test.area)) != 0 {
+			t.Errorf("%s: Area() = %v, want %d", test.name, got, test.area)
+		}
+		if got := polygon.TwiceArea(); got.Cmp(utilities.NewNumber(test.twiceArea)) != 0 {
+			t.Errorf("%s: TwiceArea() = %v, want %d", test.name, got, test.twiceArea)
+		}
+		if got := polygon.Perimeter(); math.Abs(got-test.perimeter) > 1e-9 {
+			t.Errorf("%s: Perimeter() = %v, want %v", test.name, got, test.perimeter)
+		}
+		if got := polygon.BoundaryPoints(); got.Cmp(utilities.NewNumber(test.boundary)) != 0 {
+			t.Errorf("%s: BoundaryPoints() = %v, want %d", test.name, got, test.boundary)
+		}
+		if got := polygon.InteriorPoints(); got.Cmp(utilities.NewNumber(test.interior)) != 0 {
+			t.Errorf("%s: InteriorPoints() = %v, want %d", test.name, got, test.interior)
+		}
+	}
+}
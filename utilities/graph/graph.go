@@ -0,0 +1,282 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCycle      = errors.New("graph contains a cycle")
+	ErrUndirected = errors.New("operation requires a directed graph")
+)
+
+// ************************************** //
+// Graph structure and related functions. //
+// ************************************** //
+type Graph[K comparable] struct {
+	directed  bool
+	nodes     []K
+	index     map[K]int
+	adjacency map[K][]Edge[K]
+	edges     []Edge[K]
+}
+
+type Edge[K comparable] struct {
+	From  K
+	To    K
+	Label string
+}
+
+func NewDirected[K comparable]() *Graph[K] {
+	return newGraph[K](true)
+}
+
+func NewUndirected[K comparable]() *Graph[K] {
+	return newGraph[K](false)
+}
+
+func newGraph[K comparable](directed bool) *Graph[K] {
+	g := new(Graph[K])
+	g.directed = directed
+	g.nodes = make([]K, 0)
+	g.index = make(map[K]int)
+	g.adjacency = make(map[K][]Edge[K])
+	g.edges = make([]Edge[K], 0)
+	return g
+}
+
+func (g *Graph[_]) Directed() bool {
+	return g.directed
+}
+
+// Add a node if it isn't in the graph already. Nodes keep their insertion order.
+func (g *Graph[K]) AddNode(node K) {
+	if _, ok := g.index[node]; ok {
+		return
+	}
+	g.index[node] = len(g.nodes)
+	g.nodes = append(g.nodes, node)
+}
+
+// Add an edge, adding missing nodes along the way. Undirected edges are usable
+// from both ends.
+func (g *Graph[K]) AddEdge(from K, to K, label string) {
+	g.AddNode(from)
+	g.AddNode(to)
+
+	edge := Edge[K]{From: from, To: to, Label: label}
+	g.edges = append(g.edges, edge)
+	g.adjacency[from] = append(g.adjacency[from], edge)
+	if !g.directed && from != to {
+		reverse := Edge[K]{From: to, To: from, Label: label}
+		g.adjacency[to] = append(g.adjacency[to], reverse)
+	}
+}
+
+func (g *Graph[K]) Contains(node K) bool {
+	_, ok := g.index[node]
+	return ok
+}
+
+func (g *Graph[K]) Nodes() []K {
+	return append([]K{}, g.nodes...)
+}
+
+func (g *Graph[K]) Edges() []Edge[K] {
+	return append([]Edge[K]{}, g.edges...)
+}
+
+// Edges leaving a node.
+func (g *Graph[K]) EdgesFrom(node K) []Edge[K] {
+	return append([]Edge[K]{}, g.adjacency[node]...)
+}
+
+func (g *Graph[K]) Neighbors(node K) []K {
+	neighbors := make([]K, 0)
+	for _, edge := range g.adjacency[node] {
+		neighbors = append(neighbors, edge.To)
+	}
+	return neighbors
+}
+
+// ***************** //
+// Graph algorithms. //
+// ***************** //
+
+// Nodes reachable from a start node in breadth first order, including the start.
+func (g *Graph[K]) Reachable(start K) []K {
+	if !g.Contains(start) {
+		return []K{}
+	}
+
+	visited := map[K]bool{start: true}
+	order := []K{start}
+	for i := 0; i < len(order); i++ {
+		for _, next := range g.Neighbors(order[i]) {
+			if !visited[next] {
+				visited[next] = true
+				order = append(order, next)
+			}
+		}
+	}
+	return order
+}
+
+// Order nodes so every edge points forwards using Kahn's algorithm.
+func (g *Graph[K]) TopologicalSort() ([]K, error) {
+	if !g.directed {
+		return nil, ErrUndirected
+	}
+
+	inDegree := make(map[K]int)
+	for _, edge := range g.edges {
+		inDegree[edge.To]++
+	}
+
+	queue := make([]K, 0)
+	for _, node := range g.nodes {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	order := make([]K, 0)
+	for len(queue) != 0 {
+		// Dequeue next node without any remaining incoming edges.
+		node := queue[0]
+		queue = queue[1:]
+		order = append(order, node)
+
+		for _, next := range g.Neighbors(node) {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if len(order) != len(g.nodes) {
+		return nil, ErrCycle
+	}
+	return order, nil
+}
+
+// Strongly connected components using Tarjan's algorithm. For undirected graphs
+// these are the connected components.
+func (g *Graph[K]) StronglyConnectedComponents() [][]K {
+	tarjan := &tarjanState[K]{
+		graph:   g,
+		index:   make(map[K]int),
+		lowLink: make(map[K]int),
+		onStack: make(map[K]bool),
+	}
+
+	for _, node := range g.nodes {
+		if _, visited := tarjan.index[node]; !visited {
+			tarjan.connect(node)
+		}
+	}
+	return tarjan.components
+}
+
+type tarjanState[K comparable] struct {
+	graph      *Graph[K]
+	counter    int
+	index      map[K]int
+	lowLink    map[K]int
+	onStack    map[K]bool
+	stack      []K
+	components [][]K
+}
+
+func (t *tarjanState[K]) connect(node K) {
+	t.index[node] = t.counter
+	t.lowLink[node] = t.counter
+	t.counter++
+	t.stack = append(t.stack, node)
+	t.onStack[node] = true
+
+	for _, next := range t.graph.Neighbors(node) {
+		if _, visited := t.index[next]; !visited {
+			t.connect(next)
+			t.lowLink[node] = min(t.lowLink[node], t.lowLink[next])
+		} else if t.onStack[next] {
+			t.lowLink[node] = min(t.lowLink[node], t.index[next])
+		}
+	}
+
+	// Node is the root of a component, so pop the component off the stack.
+	if t.lowLink[node] == t.index[node] {
+		component := make([]K, 0)
+		for {
+			top := t.stack[len(t.stack)-1]
+			t.stack = t.stack[:len(t.stack)-1]
+			t.onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		t.components = append(t.components, component)
+	}
+}
+
+// *********************** //
+// Export to text formats. //
+// *********************** //
+
+// Graphviz DOT representation of the graph.
+func (g *Graph[K]) ToDOT() string {
+	var builder strings.Builder
+	connector := "--"
+	if g.directed {
+		builder.WriteString("digraph {\n")
+		connector = "->"
+	} else {
+		builder.WriteString("graph {\n")
+	}
+
+	for _, node := range g.nodes {
+		fmt.Fprintf(&builder, "  %q;\n", fmt.Sprint(node))
+	}
+
+	for _, edge := range g.edges {
+		fmt.Fprintf(&builder, "  %q %s %q", fmt.Sprint(edge.From), connector, fmt.Sprint(edge.To))
+		if edge.Label != "" {
+			fmt.Fprintf(&builder, " [label=%q]", edge.Label)
+		}
+		builder.WriteString(";\n")
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// Mermaid flowchart representation of the graph.
+func (g *Graph[K]) ToMermaid() string {
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+
+	// Mermaid ids are restricted, so refer to nodes by index and show the value.
+	for idx, node := range g.nodes {
+		label := strings.ReplaceAll(fmt.Sprint(node), `"`, "#quot;")
+		fmt.Fprintf(&builder, "  n%d[\"%s\"]\n", idx, label)
+	}
+
+	connector := "---"
+	if g.directed {
+		connector = "-->"
+	}
+
+	for _, edge := range g.edges {
+		from, to := g.index[edge.From], g.index[edge.To]
+		if edge.Label != "" {
+			fmt.Fprintf(&builder, "  n%d %s|%s| n%d\n", from, connector, edge.Label, to)
+		} else {
+			fmt.Fprintf(&builder, "  n%d %s n%d\n", from, connector, to)
+		}
+	}
+
+	return builder.String()
+}
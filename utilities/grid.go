@@ -5,7 +5,6 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
-	"math"
 )
 
 // ******************************************* //
@@ -35,11 +34,51 @@ func (coord Coordinates) Scale(scalar int) Coordinates {
 }
 
 func (coord Coordinates) Abs() Coordinates {
-	coord.Row = int(math.Abs(float64(coord.Row)))
-	coord.Col = int(math.Abs(float64(coord.Col)))
+	coord.Row = AbsInt(coord.Row)
+	coord.Col = AbsInt(coord.Col)
 	return coord
 }
 
+// Taxicab distance between two coordinates.
+func (coord Coordinates) Manhattan(otherCoord Coordinates) int {
+	delta := coord.Subtract(otherCoord).Abs()
+	return delta.Row + delta.Col
+}
+
+// Chessboard distance between two coordinates.
+func (coord Coordinates) Chebyshev(otherCoord Coordinates) int {
+	delta := coord.Subtract(otherCoord).Abs()
+	return max(delta.Row, delta.Col)
+}
+
+// Rotate a quarter turn clockwise about the origin, with rows increasing downwards.
+func (coord Coordinates) Rotate90() Coordinates {
+	return Coordinates{Row: coord.Col, Col: -coord.Row}
+}
+
+// Adjacent coordinates, clockwise from North. Diagonals are optionally included.
+func (coord Coordinates) Neighbors(diagonals bool) []Coordinates {
+	neighbors := make([]Coordinates, 0)
+	for _, direction := range Directions {
+		delta := direction.Delta()
+		neighbors = append(neighbors, coord.Add(delta))
+		if diagonals {
+			// Corner between this direction and the next one clockwise.
+			corner := delta.Add(delta.Rotate90())
+			neighbors = append(neighbors, coord.Add(corner))
+		}
+	}
+	return neighbors
+}
+
+// Row-major ordering for sorting.
+func (coord Coordinates) Less(otherCoord Coordinates) bool {
+	if coord.Row != otherCoord.Row {
+		return coord.Row < otherCoord.Row
+	}
+	return coord.Col < otherCoord.Col
+}
+
 func (g *Grid[T]) GetByCoord(coord Coordinates) (T, error) {
 	return g.Get(coord.Row, coord.Col)
 }
@@ -58,7 +97,31 @@ func (g *Grid[T]) CoordInGrid(coord Coordinates) bool {
 // **************************************** //
 type Vector struct {
 	Origin    Coordinates
-	Direction Coordinates
+	Direction Direction
+}
+
+// *************************************************** //
+// Box structure (top left and bottom right corners). //
+// *************************************************** //
+type Box struct {
+	Min Coordinates
+	Max Coordinates
+}
+
+// Box covering a single cell.
+func CellBox(coord Coordinates) Box {
+	return Box{Min: coord, Max: coord}
+}
+
+// Grow the box by n cells on every side.
+func (box Box) Expand(n int) Box {
+	delta := Coordinates{Row: n, Col: n}
+	return Box{Min: box.Min.Subtract(delta), Max: box.Max.Add(delta)}
+}
+
+func (box Box) Contains(coord Coordinates) bool {
+	return coord.Row >= box.Min.Row && coord.Row <= box.Max.Row &&
+		coord.Col >= box.Min.Col && coord.Col <= box.Max.Col
 }
 
 // ************************************* //
@@ -116,6 +179,28 @@ func (g *Grid[T]) Search(item T) []Coordinates {
 	return coords
 }
 
+// Coordinates inside the box in row-major order, clipped to the grid.
+func (g *Grid[T]) Window(box Box) []Coordinates {
+	coords := make([]Coordinates, 0)
+	for i := max(box.Min.Row, 0); i <= min(box.Max.Row, g.RowSize()-1); i++ {
+		for j := max(box.Min.Col, 0); j <= min(box.Max.Col, g.ColSize()-1); j++ {
+			coords = append(coords, Coordinates{i, j})
+		}
+	}
+	return coords
+}
+
+// Coordinates within radius cells of the box, excluding the box itself.
+func (g *Grid[T]) Neighborhood(box Box, radius int) []Coordinates {
+	coords := make([]Coordinates, 0)
+	for _, coord := range g.Window(box.Expand(radius)) {
+		if !box.Contains(coord) {
+			coords = append(coords, coord)
+		}
+	}
+	return coords
+}
+
 func (g *Grid[T]) Get(rowIndex int, columnIndex int) (T, error) {
 	if rowIndex < 0 || rowIndex >= g.RowSize() {
 		return *new(T), errors.New("row index out of bounds")
@@ -16,3 +16,10 @@ func OpenFile(path string) (*bufio.Scanner, *os.File) {
 	ErrorCheck(err)
 	return bufio.NewScanner(file), file
 }
+
+func AbsInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
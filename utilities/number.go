@@ -0,0 +1,136 @@
+package utilities
+
+import (
+	"flag"
+	"math"
+	"math/big"
+
+	"github.com/iSkytran/2023adventofcode/utilities/numth"
+)
+
+// When set, every number starts out in math/big instead of waiting for overflow.
+var forceBig = false
+
+// Register the flag that forces exact big integer arithmetic.
+func BigIntFlag() {
+	flag.BoolVar(&forceBig, "bigint", false, "use math/big for all arithmetic")
+}
+
+// ****************************************************** //
+// Integer that falls back to math/big when it overflows. //
+// ****************************************************** //
+type Number struct {
+	small int
+	large *big.Int
+}
+
+func NewNumber(value int) Number {
+	if forceBig {
+		return NewBigNumber(value)
+	}
+	return Number{small: value}
+}
+
+// Number that always uses math/big.
+func NewBigNumber(value int) Number {
+	return Number{large: big.NewInt(int64(value))}
+}
+
+func (n Number) IsBig() bool {
+	return n.large != nil
+}
+
+// Copy of the value as a big integer.
+func (n Number) Big() *big.Int {
+	if n.IsBig() {
+		return new(big.Int).Set(n.large)
+	}
+	return big.NewInt(int64(n.small))
+}
+
+// Value as an int, if it fits.
+func (n Number) Int() (int, bool) {
+	if !n.IsBig() {
+		return n.small, true
+	}
+	if n.large.IsInt64() && n.large.Int64() >= math.MinInt && n.large.Int64() <= math.MaxInt {
+		return int(n.large.Int64()), true
+	}
+	return 0, false
+}
+
+func (n Number) Add(other Number) Number {
+	if !n.IsBig() && !other.IsBig() {
+		if sum, ok := numth.AddChecked(n.small, other.small); ok {
+			return Number{small: sum}
+		}
+	}
+	return Number{large: new(big.Int).Add(n.Big(), other.Big())}
+}
+
+func (n Number) Subtract(other Number) Number {
+	if !n.IsBig() && !other.IsBig() {
+		if difference, ok := numth.SubChecked(n.small, other.small); ok {
+			return Number{small: difference}
+		}
+	}
+	return Number{large: new(big.Int).Sub(n.Big(), other.Big())}
+}
+
+func (n Number) Multiply(other Number) Number {
+	if !n.IsBig() && !other.IsBig() {
+		if product, ok := numth.MulChecked(n.small, other.small); ok {
+			return Number{small: product}
+		}
+	}
+	return Number{large: new(big.Int).Mul(n.Big(), other.Big())}
+}
+
+// Division truncated towards zero.
+func (n Number) Divide(other Number) Number {
+	if !n.IsBig() && !other.IsBig() {
+		// The only overflowing case is negating the smallest int.
+		if !(n.small == math.MinInt && other.small == -1) {
+			return Number{small: n.small / other.small}
+		}
+	}
+	return Number{large: new(big.Int).Quo(n.Big(), other.Big())}
+}
+
+func (n Number) Abs() Number {
+	if n.Sign() < 0 {
+		return NewNumber(0).Subtract(n)
+	}
+	return n
+}
+
+func (n Number) Sign() int {
+	if n.IsBig() {
+		return n.large.Sign()
+	}
+	switch {
+	case n.small < 0:
+		return -1
+	case n.small > 0:
+		return 1
+	}
+	return 0
+}
+
+func (n Number) Cmp(other Number) int {
+	if !n.IsBig() && !other.IsBig() {
+		switch {
+		case n.small < other.small:
+			return -1
+		case n.small > other.small:
+			return 1
+		}
+		return 0
+	}
+	return n.Big().Cmp(other.Big())
+}
+
+// Exact decimal representation.
+func (n Number) String() string {
+	return n.Big().String()
+}
@@ -0,0 +1,93 @@
+package utilities
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"unicode"
+)
+
+// *************************************** //
+// Numbers written out in a grid of runes. //
+// *************************************** //
+type NumberSpan struct {
+	Value    int
+	Text     string
+	Bounds   Box
+	Vertical bool
+}
+
+// Find runs of digits reading left to right, and optionally top to bottom. Spans
+// are in row-major order of their first digit with horizontal ones first. Single
+// digits only show up horizontally, since they read the same both ways.
+func NumberSpans(grid *Grid[rune], vertical bool) ([]*NumberSpan, error) {
+	spans := make([]*NumberSpan, 0)
+	rows, cols := grid.Shape()
+
+	for i := 0; i < rows; i++ {
+		for j := 0; j < len(grid.Data[i]); {
+			start := j
+			for j < len(grid.Data[i]) && unicode.IsDigit(grid.Data[i][j]) {
+				j++
+			}
+			if j == start {
+				j++
+				continue
+			}
+
+			box := Box{Min: Coordinates{i, start}, Max: Coordinates{i, j - 1}}
+			span, err := newNumberSpan(string(grid.Data[i][start:j]), box, false)
+			if err != nil {
+				return nil, err
+			}
+			spans = append(spans, span)
+		}
+	}
+
+	if !vertical {
+		return spans, nil
+	}
+
+	verticalSpans := make([]*NumberSpan, 0)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; {
+			start := i
+			digits := make([]rune, 0)
+			for i < rows && j < len(grid.Data[i]) && unicode.IsDigit(grid.Data[i][j]) {
+				digits = append(digits, grid.Data[i][j])
+				i++
+			}
+			if len(digits) < 2 {
+				i = max(i, start+1)
+				continue
+			}
+
+			box := Box{Min: Coordinates{start, j}, Max: Coordinates{i - 1, j}}
+			span, err := newNumberSpan(string(digits), box, true)
+			if err != nil {
+				return nil, err
+			}
+			verticalSpans = append(verticalSpans, span)
+		}
+	}
+
+	// Columns were scanned first, so restore row-major order.
+	slices.SortFunc(verticalSpans, func(a, b *NumberSpan) int {
+		switch {
+		case a.Bounds.Min.Less(b.Bounds.Min):
+			return -1
+		case b.Bounds.Min.Less(a.Bounds.Min):
+			return 1
+		}
+		return 0
+	})
+	return append(spans, verticalSpans...), nil
+}
+
+func newNumberSpan(text string, bounds Box, vertical bool) (*NumberSpan, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return nil, fmt.Errorf("number %s at row %d col %d is out of range", text, bounds.Min.Row, bounds.Min.Col)
+	}
+	return &NumberSpan{Value: value, Text: text, Bounds: bounds, Vertical: vertical}, nil
+}
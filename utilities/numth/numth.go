@@ -0,0 +1,261 @@
+package numth
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+var (
+	ErrOverflow   = errors.New("integer overflow")
+	ErrNoSolution = errors.New("no solution")
+	ErrNoInverse  = errors.New("no modular inverse")
+	ErrModulus    = errors.New("modulus must be positive")
+	ErrMismatch   = errors.New("residues and moduli differ in length")
+)
+
+// Greatest common divisor using the Euclidean algorithm. Always non-negative.
+func GCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Least common multiple of a list of numbers. Returns ErrOverflow instead of
+// silently wrapping around.
+func LCM(nums ...int) (int, error) {
+	current := 1
+	for _, num := range nums {
+		if num == 0 {
+			return 0, nil
+		}
+
+		num = abs(num)
+		product, ok := MulChecked(current/GCD(current, num), num)
+		if !ok {
+			return 0, ErrOverflow
+		}
+		current = product
+	}
+	return current, nil
+}
+
+// Extended Euclidean algorithm. Returns g = gcd(a, b) along with x and y such that
+// a*x + b*y = g.
+func ExtendedGCD(a, b int) (g, x, y int) {
+	oldR, r := a, b
+	oldX, x := 1, 0
+	oldY, y := 0, 1
+	for r != 0 {
+		quotient := oldR / r
+		oldR, r = r, oldR-quotient*r
+		oldX, x = x, oldX-quotient*x
+		oldY, y = y, oldY-quotient*y
+	}
+
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// Multiplicative inverse of a modulo m.
+func ModInverse(a, m int) (int, error) {
+	if m <= 0 {
+		return 0, ErrModulus
+	}
+
+	g, x, _ := ExtendedGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, ErrNoInverse
+	}
+	return Mod(x, m), nil
+}
+
+// Computes base^exp modulo mod without overflowing intermediate products.
+func ModPow(base, exp, mod int) (int, error) {
+	if mod <= 0 {
+		return 0, ErrModulus
+	}
+	if exp < 0 {
+		// Negative exponents use the inverse of the base.
+		inverse, err := ModInverse(base, mod)
+		if err != nil {
+			return 0, err
+		}
+		base, exp = inverse, -exp
+	}
+
+	result := 1 % mod
+	base = Mod(base, mod)
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, mod)
+		}
+		base = MulMod(base, base, mod)
+		exp >>= 1
+	}
+	return result, nil
+}
+
+// Chinese Remainder Theorem. Finds x with 0 <= x < m such that x is congruent to
+// each residue modulo the matching modulus, where m is the least common multiple
+// of the moduli. Moduli do not need to be pairwise coprime.
+func CRT(residues, moduli []int) (x, m int, err error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, ErrMismatch
+	}
+
+	x, m = 0, 1
+	for i := range residues {
+		if moduli[i] <= 0 {
+			return 0, 0, ErrModulus
+		}
+		x, m, err = mergeCongruences(x, m, Mod(residues[i], moduli[i]), moduli[i])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return x, m, nil
+}
+
+// Combine x = r1 (mod m1) and x = r2 (mod m2) into a single congruence.
+func mergeCongruences(r1, m1, r2, m2 int) (int, int, error) {
+	g := GCD(m1, m2)
+	diff := r2 - r1
+	if diff%g != 0 {
+		return 0, 0, ErrNoSolution
+	}
+
+	lcm, ok := MulChecked(m1/g, m2)
+	if !ok {
+		return 0, 0, ErrOverflow
+	}
+
+	// Solve m1*k = diff (mod m2) for k, then x = r1 + m1*k.
+	reduced := m2 / g
+	inverse, err := ModInverse(m1/g, reduced)
+	if err != nil {
+		return 0, 0, err
+	}
+	k := MulMod(Mod(diff/g, reduced), inverse, reduced)
+
+	// m1*k is below lcm - m1 and r1 is below m1, so neither can overflow.
+	return r1 + m1*k, lcm, nil
+}
+
+// Multiplies two integers and reports whether the result fit.
+func MulChecked(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, false
+	}
+	return product, true
+}
+
+// Adds two integers and reports whether the result fit.
+func AddChecked(a, b int) (int, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// Subtracts two integers and reports whether the result fit.
+func SubChecked(a, b int) (int, bool) {
+	difference := a - b
+	if (b > 0 && difference > a) || (b < 0 && difference < a) {
+		return 0, false
+	}
+	return difference, true
+}
+
+// Computes a*b modulo m using a double width intermediate product.
+func MulMod(a, b, m int) int {
+	a, b = Mod(a, m), Mod(b, m)
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	_, rem := bits.Div64(hi, lo, uint64(m))
+	return int(rem)
+}
+
+// Euclidean modulo, always in the range [0, m).
+func Mod(a, m int) int {
+	result := a % m
+	if result < 0 {
+		result += m
+	}
+	return result
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// ****************************** //
+// Arbitrary precision fallbacks. //
+// ****************************** //
+
+// Least common multiple of a list of numbers without any risk of overflow.
+func BigLCM(nums ...int) *big.Int {
+	current := big.NewInt(1)
+	for _, num := range nums {
+		value := big.NewInt(int64(abs(num)))
+		if value.Sign() == 0 {
+			return value
+		}
+
+		g := new(big.Int).GCD(nil, nil, current, value)
+		current.Div(current, g).Mul(current, value)
+	}
+	return current
+}
+
+// Chinese Remainder Theorem without any risk of overflow. See CRT.
+func BigCRT(residues, moduli []int) (x, m *big.Int, err error) {
+	if len(residues) != len(moduli) {
+		return nil, nil, ErrMismatch
+	}
+
+	x, m = big.NewInt(0), big.NewInt(1)
+	for i := range residues {
+		if moduli[i] <= 0 {
+			return nil, nil, ErrModulus
+		}
+
+		r2, m2 := big.NewInt(int64(residues[i])), big.NewInt(int64(moduli[i]))
+		r2.Mod(r2, m2)
+
+		g := new(big.Int).GCD(nil, nil, m, m2)
+		diff := new(big.Int).Sub(r2, x)
+		if new(big.Int).Mod(diff, g).Sign() != 0 {
+			return nil, nil, ErrNoSolution
+		}
+
+		// Solve m*k = diff (mod m2) for k, then x = x + m*k.
+		reduced := new(big.Int).Div(m2, g)
+		inverse := new(big.Int).ModInverse(new(big.Int).Div(m, g), reduced)
+		if inverse == nil {
+			// Only happens when the reduced modulus is one.
+			inverse = big.NewInt(0)
+		}
+		k := diff.Div(diff, g)
+		k.Mul(k, inverse).Mod(k, reduced)
+
+		x.Add(x, k.Mul(k, m))
+		m.Mul(m, reduced)
+	}
+	return x, m, nil
+}
@@ -0,0 +1,101 @@
+package utilities
+
+// ***************************************** //
+// 3D point structure and related functions. //
+// ***************************************** //
+type Point3 struct {
+	X int
+	Y int
+	Z int
+}
+
+// Axis to rotate a point about.
+type Axis int
+
+const (
+	XAxis Axis = iota
+	YAxis
+	ZAxis
+)
+
+func (point Point3) Add(otherPoint Point3) Point3 {
+	x := point.X + otherPoint.X
+	y := point.Y + otherPoint.Y
+	z := point.Z + otherPoint.Z
+	return Point3{X: x, Y: y, Z: z}
+}
+
+func (point Point3) Subtract(otherPoint Point3) Point3 {
+	x := point.X - otherPoint.X
+	y := point.Y - otherPoint.Y
+	z := point.Z - otherPoint.Z
+	return Point3{X: x, Y: y, Z: z}
+}
+
+func (point Point3) Scale(scalar int) Point3 {
+	x := scalar * point.X
+	y := scalar * point.Y
+	z := scalar * point.Z
+	return Point3{X: x, Y: y, Z: z}
+}
+
+func (point Point3) Abs() Point3 {
+	point.X = AbsInt(point.X)
+	point.Y = AbsInt(point.Y)
+	point.Z = AbsInt(point.Z)
+	return point
+}
+
+// Taxicab distance between two points.
+func (point Point3) Manhattan(otherPoint Point3) int {
+	delta := point.Subtract(otherPoint).Abs()
+	return delta.X + delta.Y + delta.Z
+}
+
+// Chessboard distance between two points.
+func (point Point3) Chebyshev(otherPoint Point3) int {
+	delta := point.Subtract(otherPoint).Abs()
+	return max(delta.X, delta.Y, delta.Z)
+}
+
+// Rotate a quarter turn counterclockwise about an axis, looking from the positive
+// end of the axis towards the origin.
+func (point Point3) Rotate90(axis Axis) Point3 {
+	switch axis {
+	case XAxis:
+		return Point3{X: point.X, Y: -point.Z, Z: point.Y}
+	case YAxis:
+		return Point3{X: point.Z, Y: point.Y, Z: -point.X}
+	default:
+		return Point3{X: -point.Y, Y: point.X, Z: point.Z}
+	}
+}
+
+// Adjacent points sharing a face. Points sharing an edge or corner are optionally
+// included.
+func (point Point3) Neighbors(diagonals bool) []Point3 {
+	neighbors := make([]Point3, 0)
+	for x := -1; x <= 1; x++ {
+		for y := -1; y <= 1; y++ {
+			for z := -1; z <= 1; z++ {
+				delta := Point3{X: x, Y: y, Z: z}
+				distance := delta.Manhattan(Point3{})
+				if distance == 1 || (diagonals && distance > 1) {
+					neighbors = append(neighbors, point.Add(delta))
+				}
+			}
+		}
+	}
+	return neighbors
+}
+
+// Lexicographic ordering by X, then Y, then Z for sorting.
+func (point Point3) Less(otherPoint Point3) bool {
+	if point.X != otherPoint.X {
+		return point.X < otherPoint.X
+	}
+	if point.Y != otherPoint.Y {
+		return point.Y < otherPoint.Y
+	}
+	return point.Z < otherPoint.Z
+}
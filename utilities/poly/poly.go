@@ -0,0 +1,129 @@
+package poly
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/iSkytran/2023adventofcode/utilities"
+)
+
+var ErrEmpty = errors.New("sequence has no values")
+
+// ****************************************************** //
+// Newton forward difference table and related functions. //
+// ****************************************************** //
+
+// Difference table of a sequence sampled at indices 0, 1, 2, ... Only the leading
+// entry of each row is needed to evaluate the polynomial. Entries fall back to
+// math/big rather than overflowing.
+type DifferenceTable struct {
+	leading []utilities.Number
+	settled bool
+}
+
+// Build the difference table once. If no row of differences becomes all zeroes
+// before running out of values, the table holds the exact fit of degree n-1.
+func NewDifferenceTable(values []int) (*DifferenceTable, error) {
+	if len(values) == 0 {
+		return nil, ErrEmpty
+	}
+
+	table := new(DifferenceTable)
+	table.leading = make([]utilities.Number, 0)
+
+	row := make([]utilities.Number, 0)
+	for _, value := range values {
+		row = append(row, utilities.NewNumber(value))
+	}
+
+	for !allZeroes(row) {
+		table.leading = append(table.leading, row[0])
+		if len(row) == 1 {
+			// Ran out of values before the differences settled.
+			return table, nil
+		}
+		row = differences(row)
+	}
+
+	table.settled = true
+	return table, nil
+}
+
+// Check if the differences reached a row of zeroes, meaning the sequence is
+// polynomial within its length rather than just fitted exactly.
+func (table *DifferenceTable) Settled() bool {
+	return table.settled
+}
+
+// Degree of the fitted polynomial. The zero polynomial has degree -1.
+func (table *DifferenceTable) Degree() int {
+	return len(table.leading) - 1
+}
+
+// Value of the polynomial at index k, which may be negative or beyond the end of
+// the sequence. Uses Newton's forward formula, which is a sum over binomial
+// coefficients of k.
+func (table *DifferenceTable) At(k int) utilities.Number {
+	value := utilities.NewNumber(0)
+	binomial := utilities.NewNumber(1)
+	for j, delta := range table.leading {
+		value = value.Add(binomial.Multiply(delta))
+
+		// C(k, j+1) = C(k, j) * (k - j) / (j + 1), which always divides exactly.
+		factor := utilities.NewNumber(k).Subtract(utilities.NewNumber(j))
+		binomial = binomial.Multiply(factor).Divide(utilities.NewNumber(j + 1))
+	}
+	return value
+}
+
+// Exact coefficients of the polynomial in increasing powers of the index.
+func (table *DifferenceTable) Coefficients() []*big.Rat {
+	coefficients := make([]*big.Rat, max(len(table.leading), 1))
+	for i := range coefficients {
+		coefficients[i] = new(big.Rat)
+	}
+
+	// Falling factorial x(x-1)...(x-j+1) as integer coefficients, along with j!.
+	falling := []*big.Int{big.NewInt(1)}
+	factorial := big.NewInt(1)
+	for j, delta := range table.leading {
+		// Add delta * falling / j! to the result.
+		for power, coefficient := range falling {
+			term := new(big.Rat).SetFrac(new(big.Int).Mul(coefficient, delta.Big()), factorial)
+			coefficients[power].Add(coefficients[power], term)
+		}
+
+		// Multiply falling by (x - j) and update the factorial.
+		next := make([]*big.Int, len(falling)+1)
+		for i := range next {
+			next[i] = new(big.Int)
+		}
+		for power, coefficient := range falling {
+			next[power+1].Add(next[power+1], coefficient)
+			next[power].Sub(next[power], new(big.Int).Mul(coefficient, big.NewInt(int64(j))))
+		}
+		falling = next
+		factorial.Mul(factorial, big.NewInt(int64(j+1)))
+	}
+
+	return coefficients
+}
+
+func differences(values []utilities.Number) []utilities.Number {
+	// Compute differences between each element.
+	deltas := make([]utilities.Number, 0)
+	for i := 1; i < len(values); i++ {
+		delta := values[i].Subtract(values[i-1])
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+func allZeroes(values []utilities.Number) bool {
+	for _, val := range values {
+		if val.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
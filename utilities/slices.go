@@ -1,7 +1,6 @@
 package utilities
 
 import (
-	"math"
 	"strconv"
 )
 
@@ -15,7 +14,7 @@ func CreateSlice[T comparable](size int, initial T) []T {
 
 func ElementHammingDistance[T comparable](s1 []T, s2 []T) int {
 	if len(s1) != len(s2) {
-		return int(math.Abs(float64(len(s1) - len(s2))))
+		return AbsInt(len(s1) - len(s2))
 	}
 
 	distance := 0
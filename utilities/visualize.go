@@ -0,0 +1,161 @@
+package utilities
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ANSI escape sequences used when drawing to a terminal.
+const (
+	AnsiReset     = "\x1b[0m"
+	AnsiBold      = "\x1b[1m"
+	AnsiDim       = "\x1b[2m"
+	AnsiRed       = "\x1b[31m"
+	AnsiGreen     = "\x1b[32m"
+	AnsiYellow    = "\x1b[33m"
+	AnsiBlue      = "\x1b[34m"
+	AnsiMagenta   = "\x1b[35m"
+	AnsiCyan      = "\x1b[36m"
+	AnsiHighlight = "\x1b[1;30;43m"
+	ansiHome      = "\x1b[H"
+	ansiClear     = "\x1b[2J"
+)
+
+// Rune drawn in place of highlighted cells when colors are unavailable.
+const plainHighlight = '*'
+
+// ***************************************** //
+// Visualizer interface and implementations. //
+// ***************************************** //
+type Visualizer interface {
+	// Draw a single frame of the grid. Highlighted coordinates may be nil.
+	Frame(grid *Grid[rune], highlight *Set[Coordinates])
+	Close() error
+}
+
+// Visualizer that discards every frame.
+type NoopVisualizer struct{}
+
+func (NoopVisualizer) Frame(*Grid[rune], *Set[Coordinates]) {}
+
+func (NoopVisualizer) Close() error {
+	return nil
+}
+
+// Visualizer that redraws frames to a writer, either in place with ANSI colors
+// or appended one after another as plain text.
+type StreamVisualizer struct {
+	writer  *bufio.Writer
+	closer  io.Closer
+	delay   time.Duration
+	ansi    bool
+	palette map[rune]string
+	frames  int
+}
+
+func NewTerminalVisualizer(out io.Writer, fps int, palette map[rune]string) *StreamVisualizer {
+	v := new(StreamVisualizer)
+	v.writer = bufio.NewWriter(out)
+	v.ansi = true
+	v.palette = palette
+	if fps > 0 {
+		v.delay = time.Second / time.Duration(fps)
+	}
+	return v
+}
+
+func NewFileVisualizer(file io.WriteCloser) *StreamVisualizer {
+	v := new(StreamVisualizer)
+	v.writer = bufio.NewWriter(file)
+	v.closer = file
+	return v
+}
+
+func (v *StreamVisualizer) Frame(grid *Grid[rune], highlight *Set[Coordinates]) {
+	v.frames++
+	if v.ansi {
+		// Move the cursor home and clear instead of scrolling.
+		v.writer.WriteString(ansiHome + ansiClear)
+	} else {
+		fmt.Fprintf(v.writer, "Frame %d\n", v.frames)
+	}
+
+	for rowIdx, row := range grid.Data {
+		for colIdx, r := range row {
+			highlighted := highlight != nil && highlight.Contains(Coordinates{Row: rowIdx, Col: colIdx})
+			v.writeRune(r, highlighted)
+		}
+		v.writer.WriteRune('\n')
+	}
+
+	if !v.ansi {
+		// Blank line between frames.
+		v.writer.WriteRune('\n')
+	}
+	v.writer.Flush()
+
+	time.Sleep(v.delay)
+}
+
+func (v *StreamVisualizer) writeRune(r rune, highlighted bool) {
+	switch {
+	case !v.ansi && highlighted:
+		v.writer.WriteRune(plainHighlight)
+	case !v.ansi:
+		v.writer.WriteRune(r)
+	case highlighted:
+		v.writer.WriteString(AnsiHighlight + string(r) + AnsiReset)
+	default:
+		if color, ok := v.palette[r]; ok {
+			v.writer.WriteString(color + string(r) + AnsiReset)
+		} else {
+			v.writer.WriteRune(r)
+		}
+	}
+}
+
+func (v *StreamVisualizer) Close() error {
+	err := v.writer.Flush()
+	if v.closer != nil {
+		if closeErr := v.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// ********************************************** //
+// Command line options for creating visualizers. //
+// ********************************************** //
+type VisualizerOptions struct {
+	Enabled bool
+	FPS     int
+	Output  string
+}
+
+// Register the visualizer flags on the default command line flag set.
+func VisualizerFlags() *VisualizerOptions {
+	opts := new(VisualizerOptions)
+	flag.BoolVar(&opts.Enabled, "visualize", false, "animate the simulation frame by frame")
+	flag.IntVar(&opts.FPS, "fps", 10, "frames per second of the animation")
+	flag.StringVar(&opts.Output, "frames", "", "write frames to this file instead of the terminal")
+	return opts
+}
+
+func (opts *VisualizerOptions) NewVisualizer(palette map[rune]string) Visualizer {
+	if !opts.Enabled {
+		return NoopVisualizer{}
+	}
+
+	if opts.Output == "" {
+		return NewTerminalVisualizer(os.Stdout, opts.FPS, palette)
+	}
+
+	file, err := os.Create(opts.Output)
+	ErrorCheck(err)
+	return NewFileVisualizer(file)
+}